@@ -1,9 +1,20 @@
 package main
 
 import (
+	"flag"
+
 	"github.com/palemoky/fight-the-landlord-go/internal/ui"
 )
 
 func main() {
-	ui.Start()
+	seed := flag.Int64("seed", 0, "第一局的随机种子，相同的种子发出相同的牌；为 0 时随机")
+	hands := flag.Int("hands", 0, "比赛的局数，为 0 时一直玩到退出")
+	target := flag.Int("target", 0, "有玩家累计得分达到正负目标分时比赛结束，为 0 时不限")
+	replay := flag.String("replay", "", "回放指定的棋谱文件")
+	resume := flag.String("resume", "", "从指定的存档继续对局，对局中按 Ctrl+S 存档并退出")
+	practice := flag.Bool("practice", false, "练习模式，对局中按 Ctrl+Z 悔棋、Ctrl+Y 重做")
+	rules := flag.String("rules", "classic", "牌型规则：classic（经典）、mobile（手机常见规则）、tournament（比赛规则）、laizi（癞子）、four（四人两副牌）或 two（两人）")
+	flag.Parse()
+
+	ui.Start(ui.Config{Seed: *seed, Hands: *hands, TargetScore: *target, Replay: *replay, Resume: *resume, Practice: *practice, Rules: *rules})
 }
@@ -0,0 +1,9 @@
+package bot
+
+import (
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+)
+
+func sameRanks(a, b []card.Card) bool {
+	return len(a) == len(b) && card.CountRanks(a) == card.CountRanks(b)
+}
@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/infer"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Heuristic 基于规则的电脑玩家
+// 它用 rule.Decompose 把手牌拆成手数最少的一组牌型，先出最弱的组合；跟牌时尽量不拆牌，
+// 留着炸弹和王炸等到关键时刻再用，队友出的牌一般不压。
+type Heuristic struct{}
+
+// NewHeuristic 创建一个 Heuristic 电脑玩家
+func NewHeuristic() *Heuristic {
+	return &Heuristic{}
+}
+
+// Bid 实现 game.Agent，按手牌的牌力评分决定叫几分
+func (h *Heuristic) Bid(view game.PlayerView) game.BidAction {
+	return SuggestBid(view)
+}
+
+// SuggestBid 按 rule.Evaluate 的牌力评分从 view.ValidBids 中选出叫地主的动作
+// 叫分时最多叫到建议的分数，抢地主时建议叫 1 分以上就叫，2 分以上才抢。
+func SuggestBid(view game.PlayerView) game.BidAction {
+	want := game.BidPass + game.BidAction(rule.Evaluate(view.Rules, view.Hand).BidLevel())
+
+	best := game.BidPass
+	for _, a := range view.ValidBids {
+		switch a {
+		case game.BidOne, game.BidTwo, game.BidThree:
+			if a <= want {
+				best = a
+			}
+		case game.BidCall:
+			if want >= game.BidOne {
+				best = a
+			}
+		case game.BidRob:
+			if want >= game.BidTwo {
+				best = a
+			}
+		}
+	}
+	return best
+}
+
+// Play 实现 game.Agent
+func (h *Heuristic) Play(view game.PlayerView) rule.ParsedHand {
+	if view.FreePlay() {
+		return h.lead(view)
+	}
+	return h.follow(view)
+}
+
+// lead 自由出牌：一般先出最弱的一手，快出完时先出没人要得起的牌
+func (h *Heuristic) lead(view game.PlayerView) rule.ParsedHand {
+	groups := rule.Decompose(view.Rules, view.Hand)
+	if len(groups) == 1 {
+		return groups[0]
+	}
+
+	if len(groups) == 2 {
+		for _, g := range groups {
+			if !opponentsCanBeat(view, g) {
+				return g
+			}
+		}
+	}
+
+	// 下家是只剩一张牌的队友，送一张小单牌让他走
+	next := (view.Seat + 1) % len(view.HandSizes)
+	if view.IsTeammate(next) && view.HandSizes[next] == 1 {
+		if singles := filter(groups, func(g rule.ParsedHand) bool { return g.Type == rule.Single }); len(singles) > 0 {
+			return weakest(singles)
+		}
+	}
+
+	candidates := filter(groups, func(g rule.ParsedHand) bool { return !g.Type.IsBomb() })
+	if len(candidates) == 0 {
+		candidates = groups
+	}
+
+	// 对手快出完时，避免出他可能接得住的单张或对子
+	danger := minOpponentCards(view)
+	if danger <= 2 {
+		safe := filter(candidates, func(g rule.ParsedHand) bool { return len(g.Cards) > danger })
+		if len(safe) > 0 {
+			return weakest(safe)
+		}
+		return strongest(candidates)
+	}
+	return weakest(candidates)
+}
+
+// follow 跟牌：尽量用不拆牌的最小组合压，必要时才动用炸弹
+func (h *Heuristic) follow(view game.PlayerView) rule.ParsedHand {
+	last := view.LastPlayedHand
+	plays := rule.EnumerateLegalPlays(view.Rules, view.Hand, last)
+	if len(plays) == 0 {
+		return rule.ParsedHand{}
+	}
+	for _, p := range plays {
+		if len(p.Cards) == len(view.Hand) {
+			return p // 一手出完
+		}
+	}
+	// 队友的牌一般不压
+	if view.IsTeammate(view.LastPlayerIdx) {
+		return rule.ParsedHand{}
+	}
+
+	groups := len(rule.Decompose(view.Rules, view.Hand))
+	danger := view.HandSizes[view.LastPlayerIdx] <= 4 || minOpponentCards(view) <= 2
+
+	var best *rule.ParsedHand
+	bestCost := 0
+	for i, p := range plays {
+		if p.Type.IsBomb() {
+			continue
+		}
+		// 代价：出完这手牌后手数的变化，正好是拆好的一组时为 -1
+		cost := len(rule.Decompose(view.Rules, card.RemoveRanks(view.Hand, p.Cards))) - groups
+		if best == nil || cost < bestCost {
+			best, bestCost = &plays[i], cost
+		}
+	}
+	if best != nil {
+		// 对手牌还多时，不为了压小牌拆牌或交出 2 和王
+		spendsControl := best.KeyRank >= card.Rank2 && last.KeyRank < card.RankJ
+		if danger || (bestCost <= 0 && !spendsControl) || (bestCost == 1 && !spendsControl && last.KeyRank >= card.Rank10) {
+			return *best
+		}
+	}
+
+	// 炸弹：对手快出完，或者炸完之后自己也快出完了
+	for _, p := range plays {
+		if !p.Type.IsBomb() {
+			continue
+		}
+		if danger || len(rule.Decompose(view.Rules, card.RemoveRanks(view.Hand, p.Cards))) <= 1 {
+			return p
+		}
+	}
+	return rule.ParsedHand{}
+}
+
+// minOpponentCards 返回对手中最少的剩余手牌数
+func minOpponentCards(view game.PlayerView) int {
+	least := -1
+	for seat, n := range view.HandSizes {
+		if !view.IsTeammate(seat) && (least < 0 || n < least) {
+			least = n
+		}
+	}
+	return least
+}
+
+// opponentsCanBeat 按推断出的每个对手可能有的牌，判断有没有对手压得住 hand
+func opponentsCanBeat(view game.PlayerView, hand rule.ParsedHand) bool {
+	for seat, h := range infer.Infer(view).Seats {
+		if view.IsTeammate(seat) {
+			continue
+		}
+		var counts card.RankCounts
+		for r, n := range h.Max {
+			counts[r] = n
+		}
+		if rule.CanBeatWithHand(view.Rules, counts.Cards(), hand) {
+			return true
+		}
+	}
+	return false
+}
+
+func filter(groups []rule.ParsedHand, keep func(rule.ParsedHand) bool) []rule.ParsedHand {
+	var result []rule.ParsedHand
+	for _, g := range groups {
+		if keep(g) {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// weakest 关键牌最小的一手，相同时优先出牌更多的
+func weakest(groups []rule.ParsedHand) rule.ParsedHand {
+	best := groups[0]
+	for _, g := range groups[1:] {
+		if g.KeyRank < best.KeyRank || (g.KeyRank == best.KeyRank && len(g.Cards) > len(best.Cards)) {
+			best = g
+		}
+	}
+	return best
+}
+
+// strongest 关键牌最大的一手
+func strongest(groups []rule.ParsedHand) rule.ParsedHand {
+	best := groups[0]
+	for _, g := range groups[1:] {
+		if g.KeyRank > best.KeyRank {
+			best = g
+		}
+	}
+	return best
+}
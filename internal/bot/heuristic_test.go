@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parsed(t *testing.T, ranks ...card.Rank) rule.ParsedHand {
+	hands, err := rule.ParseHand(rule.Classic, testCards(ranks...))
+	require.NoError(t, err)
+	return hands[0]
+}
+
+// viewFor builds a playing-phase view with seat 0 as landlord unless told otherwise.
+func viewFor(seat, landlord int, hand []card.Card, sizes []int, last rule.ParsedHand, lastPlayer int) game.PlayerView {
+	unseen := map[card.Rank]int{}
+	for r := card.Rank3; r <= card.Rank2; r++ {
+		unseen[r] = 4
+	}
+	unseen[card.RankBlackJoker], unseen[card.RankRedJoker] = 1, 1
+	for _, c := range hand {
+		unseen[c.Rank]--
+	}
+	return game.PlayerView{
+		Seat:           seat,
+		Hand:           hand,
+		Phase:          game.PhasePlaying,
+		LandlordSeat:   landlord,
+		HandSizes:      sizes,
+		Unseen:         unseen,
+		LastPlayedHand: last,
+		LastPlayerIdx:  lastPlayer,
+	}
+}
+
+// TestHeuristic_Play uses a table to test lead and follow decisions.
+func TestHeuristic_Play(t *testing.T) {
+	testCases := []struct {
+		name     string
+		view     func() game.PlayerView
+		expected []card.Rank
+	}{
+		{
+			name: "leads the weakest group",
+			view: func() game.PlayerView {
+				hand := testCards(card.Rank4, card.Rank4, card.Rank9, card.RankA, card.RankA)
+				return viewFor(0, 0, hand, []int{5, 17, 17}, rule.ParsedHand{}, 0)
+			},
+			expected: []card.Rank{card.Rank4, card.Rank4},
+		},
+		{
+			name: "plays out the whole hand when possible",
+			view: func() game.PlayerView {
+				hand := testCards(card.Rank7, card.Rank7)
+				return viewFor(1, 0, hand, []int{10, 2, 17}, parsed(t, card.Rank5, card.Rank5), 0)
+			},
+			expected: []card.Rank{card.Rank7, card.Rank7},
+		},
+		{
+			name: "follows without breaking a pair",
+			view: func() game.PlayerView {
+				hand := testCards(card.Rank8, card.Rank8, card.RankJ, card.Rank4, card.Rank3)
+				return viewFor(1, 0, hand, []int{15, 5, 17}, parsed(t, card.Rank7), 0)
+			},
+			expected: []card.Rank{card.RankJ},
+		},
+		{
+			name: "does not beat a teammate",
+			view: func() game.PlayerView {
+				hand := testCards(card.RankA, card.Rank3, card.Rank5)
+				return viewFor(2, 0, hand, []int{15, 8, 3}, parsed(t, card.Rank9), 1)
+			},
+			expected: []card.Rank{},
+		},
+		{
+			name: "holds the bomb against a long hand",
+			view: func() game.PlayerView {
+				hand := testCards(card.Rank6, card.Rank6, card.Rank6, card.Rank6, card.Rank3, card.Rank4, card.Rank9)
+				return viewFor(1, 0, hand, []int{15, 7, 17}, parsed(t, card.Rank2), 0)
+			},
+			expected: []card.Rank{},
+		},
+		{
+			name: "bombs when the landlord is about to go out",
+			view: func() game.PlayerView {
+				hand := testCards(card.Rank6, card.Rank6, card.Rank6, card.Rank6, card.Rank3, card.Rank4, card.Rank9)
+				return viewFor(1, 0, hand, []int{2, 7, 17}, parsed(t, card.Rank2), 0)
+			},
+			expected: []card.Rank{card.Rank6, card.Rank6, card.Rank6, card.Rank6},
+		},
+		{
+			name: "avoids leading a single into a one-card opponent",
+			view: func() game.PlayerView {
+				hand := testCards(card.Rank3, card.Rank9, card.Rank9)
+				return viewFor(1, 0, hand, []int{1, 3, 17}, rule.ParsedHand{}, 1)
+			},
+			expected: []card.Rank{card.Rank9, card.Rank9},
+		},
+		{
+			name: "leads the single the landlord could not beat",
+			view: func() game.PlayerView {
+				hand := testCards(card.RankK, card.Rank9, card.Rank9)
+				view := viewFor(1, 0, hand, []int{3, 3, 17}, rule.ParsedHand{}, 1)
+				view.History = []game.Move{
+					{Seat: 1, Cards: testCards(card.RankQ), Hand: parsed(t, card.RankQ)},
+					{Seat: 2},
+					{Seat: 0},
+				}
+				return view
+			},
+			expected: []card.Rank{card.RankK},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.ElementsMatch(t, tc.expected, cardRanks(NewHeuristic().Play(tc.view()).Cards))
+		})
+	}
+}
+
+// TestHeuristic_Bid checks that stronger hands bid higher.
+func TestHeuristic_Bid(t *testing.T) {
+	allBids := []game.BidAction{game.BidPass, game.BidOne, game.BidTwo, game.BidThree}
+
+	weak := game.PlayerView{Hand: testCards(card.Rank3, card.Rank5, card.Rank7, card.Rank9, card.RankJ), ValidBids: allBids}
+	assert.Equal(t, game.BidPass, NewHeuristic().Bid(weak))
+
+	strong := game.PlayerView{Hand: testCards(card.RankRedJoker, card.RankBlackJoker, card.Rank2, card.Rank2, card.RankA), ValidBids: allBids}
+	assert.Equal(t, game.BidThree, NewHeuristic().Bid(strong))
+
+	rob := game.PlayerView{Hand: strong.Hand, ValidBids: []game.BidAction{game.BidPass, game.BidRob}}
+	assert.Equal(t, game.BidRob, NewHeuristic().Bid(rob))
+}
+
+// TestHeuristic_FullGame plays whole games between heuristic and simple bots.
+func TestHeuristic_FullGame(t *testing.T) {
+	for range 20 {
+		g := game.NewGame()
+		g.Deal()
+		g.Bidding()
+
+		agents := []game.Agent{NewHeuristic(), NewSimple(), NewHeuristic()}
+		require.NoError(t, g.Run(agents))
+		_, isOver := g.CheckWinner()
+		require.True(t, isOver)
+	}
+}
@@ -0,0 +1,271 @@
+package bot
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/infer"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/palemoky/fight-the-landlord-go/internal/solver"
+)
+
+const (
+	// DefaultPIMCBudget PIMC 每一步默认的思考时间
+	DefaultPIMCBudget = 2 * time.Second
+	// maxPIMCBudget 思考时间的上限，保证在出牌超时之前做出决定
+	maxPIMCBudget = game.PlayerTurnTimeout / 2
+	// maxCandidates 参与模拟的候选出法上限，多出来的按拆牌代价裁掉
+	maxCandidates = 24
+	// endgameCards 剩余总牌数不超过它时，模拟改用明牌求解器得出结果
+	endgameCards = 12
+	// endgameNodes 每次求解最多搜索的局面数，超出时退回快速模拟
+	endgameNodes = 5000
+)
+
+// PIMC 不完全信息蒙特卡洛（Perfect Information Monte Carlo）电脑玩家
+// 它用 infer.Sampler 随机生成与已知信息一致的对手手牌，并按叫地主的表现给每组手牌加权，
+// 对每个候选出法做大量模拟对局，选出平均胜率最高的一手。
+type PIMC struct {
+	Budget   time.Duration // 每一步的思考时间，不超过 game.PlayerTurnTimeout 的一半
+	Workers  int           // 并行模拟的 goroutine 数
+	Playouts int           // 每一步最多模拟的局数，0 表示只受思考时间限制
+
+	heuristic *Heuristic
+	mu        sync.Mutex
+	rng       *rand.Rand
+}
+
+// NewPIMC 创建一个 PIMC 电脑玩家，budget 或 workers 不大于 0 时使用默认值
+func NewPIMC(budget time.Duration, workers int) *PIMC {
+	return &PIMC{
+		Budget:    budget,
+		Workers:   workers,
+		heuristic: NewHeuristic(),
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Bid 实现 game.Agent，叫地主交给 Heuristic
+func (p *PIMC) Bid(view game.PlayerView) game.BidAction {
+	return p.heuristic.Bid(view)
+}
+
+// Play 实现 game.Agent
+func (p *PIMC) Play(view game.PlayerView) rule.ParsedHand {
+	candidates := p.candidates(view)
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	// 按叫地主的表现给样本加权，叫得高的人更可能拿着大牌
+	sampler := infer.NewSampler(view)
+	sampler.Weight = infer.BidWeight(view)
+	wins := make([]float64, len(candidates))
+	runs := make([]float64, len(candidates))
+	var mu sync.Mutex
+	deadline := time.Now().Add(p.budget())
+
+	var wg sync.WaitGroup
+	workers := p.workers()
+	for w := range workers {
+		rng := p.newRand()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; ; i += workers {
+				if time.Now().After(deadline) || (p.Playouts > 0 && i >= p.Playouts) {
+					return
+				}
+				c := i % len(candidates)
+				hands := sampler.Sample(rng)
+				weight := sampler.Weight(hands)
+				won := playout(view, hands, candidates[c])
+
+				mu.Lock()
+				runs[c] += weight
+				if won {
+					wins[c] += weight
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	best, bestRate := 0, -1.0
+	for c := range candidates {
+		if runs[c] == 0 {
+			continue
+		}
+		if rate := wins[c] / runs[c]; rate > bestRate {
+			best, bestRate = c, rate
+		}
+	}
+	return candidates[best]
+}
+
+func (p *PIMC) budget() time.Duration {
+	if p.Budget <= 0 {
+		return DefaultPIMCBudget
+	}
+	return min(p.Budget, maxPIMCBudget)
+}
+
+func (p *PIMC) workers() int {
+	if p.Workers <= 0 {
+		return runtime.NumCPU()
+	}
+	return p.Workers
+}
+
+func (p *PIMC) newRand() *rand.Rand {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return rand.New(rand.NewSource(p.rng.Int63()))
+}
+
+// candidates 候选出法：合法出法（按拆牌代价裁剪）、Heuristic 的选择，以及能 PASS 时的 PASS（空的 ParsedHand）
+func (p *PIMC) candidates(view game.PlayerView) []rule.ParsedHand {
+	last := view.LastPlayedHand
+	if view.FreePlay() {
+		last = rule.ParsedHand{}
+	}
+	plays := rule.EnumerateLegalPlays(view.Rules, view.Hand, last)
+	if len(plays) > maxCandidates {
+		// 拆牌越少的出法越靠前
+		groups := len(rule.Decompose(view.Rules, view.Hand))
+		costs := make([]int, len(plays))
+		order := make([]int, len(plays))
+		for i := range plays {
+			costs[i] = len(rule.Decompose(view.Rules, card.RemoveRanks(view.Hand, plays[i].Cards))) - groups
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return costs[a] - costs[b] })
+		trimmed := make([]rule.ParsedHand, 0, maxCandidates)
+		for _, i := range order[:maxCandidates] {
+			trimmed = append(trimmed, plays[i])
+		}
+		plays = trimmed
+	}
+
+	candidates := plays
+	if choice := p.heuristic.Play(view); !choice.IsEmpty() && !slices.ContainsFunc(candidates, func(c rule.ParsedHand) bool {
+		return c.SameAs(choice) && sameRanks(c.Cards, choice.Cards)
+	}) {
+		candidates = append(candidates, choice)
+	}
+	if !view.FreePlay() {
+		candidates = append(candidates, rule.ParsedHand{})
+	}
+	return candidates
+}
+
+// playout 先打出 first，再让所有座位按快速策略打完（残局交给求解器），返回自己一方是否获胜
+func playout(view game.PlayerView, hands [][]card.Card, first rule.ParsedHand) bool {
+	s := &simState{
+		rules:    view.Rules,
+		hands:    hands,
+		landlord: view.LandlordSeat,
+		turn:     view.Seat,
+		last:     view.LastPlayedHand,
+		lastSeat: view.LastPlayerIdx,
+	}
+	if view.FreePlay() {
+		s.last = rule.ParsedHand{}
+	}
+	s.apply(first)
+	mine := solver.Farmers
+	if view.Seat == s.landlord {
+		mine = solver.Landlord
+	}
+	if side, ok := s.solve(); ok {
+		return side == mine
+	}
+	for s.winner() < 0 {
+		s.apply(s.rolloutMove())
+	}
+	winner := s.winner()
+	return winner == view.Seat || (winner != s.landlord && view.Seat != s.landlord)
+}
+
+// simState 模拟对局的状态，所有座位的手牌都是已知的
+type simState struct {
+	rules    rule.RuleSet
+	hands    [][]card.Card
+	landlord int
+	turn     int
+	last     rule.ParsedHand // 需要压过的牌，自由出牌时为空
+	lastSeat int
+}
+
+// apply 当前座位打出 hand（为空表示 PASS），并把回合交给下家
+func (s *simState) apply(hand rule.ParsedHand) {
+	if !hand.IsEmpty() {
+		s.last = hand
+		s.lastSeat = s.turn
+		s.hands[s.turn] = card.RemoveRanks(s.hands[s.turn], hand.Cards)
+	}
+	s.turn = (s.turn + 1) % len(s.hands)
+	if s.turn == s.lastSeat {
+		s.last = rule.ParsedHand{} // 其他人都不要，开始新的一轮
+	}
+}
+
+// solve 剩下的牌不多时用明牌求解器算出胜方，搜索超出上限时 ok 为 false
+func (s *simState) solve() (winner solver.Side, ok bool) {
+	total := 0
+	for _, h := range s.hands {
+		total += len(h)
+	}
+	if total > endgameCards {
+		return 0, false
+	}
+	result, err := solver.New(endgameNodes).Solve(solver.Position{
+		Rules:    s.rules,
+		Hands:    s.hands,
+		Landlord: s.landlord,
+		Turn:     s.turn,
+		Last:     s.last,
+		LastSeat: s.lastSeat,
+	})
+	if err != nil {
+		return 0, false
+	}
+	return result.Winner, true
+}
+
+func (s *simState) winner() int {
+	for seat, h := range s.hands {
+		if len(h) == 0 {
+			return seat
+		}
+	}
+	return -1
+}
+
+func (s *simState) isTeammate(a, b int) bool {
+	return a == b || (a != s.landlord && b != s.landlord)
+}
+
+// rolloutMove 模拟时使用的快速策略：先出最弱的一组，跟牌用最小的同类型牌，不压队友，对手快出完时才炸
+func (s *simState) rolloutMove() rule.ParsedHand {
+	hand := s.hands[s.turn]
+	if s.last.IsEmpty() {
+		return weakest(rule.Decompose(s.rules, hand))
+	}
+	if s.isTeammate(s.turn, s.lastSeat) {
+		return rule.ParsedHand{}
+	}
+	danger := len(s.hands[s.lastSeat]) <= 2
+	for _, p := range rule.EnumerateLegalPlays(s.rules, hand, s.last) {
+		if len(p.Cards) == len(hand) || p.Type == s.last.Type || danger {
+			return p
+		}
+	}
+	return rule.ParsedHand{}
+}
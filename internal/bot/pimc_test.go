@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPIMC_Play checks that the search avoids a losing lead in a small endgame.
+func TestPIMC_Play(t *testing.T) {
+	// 我是农民（座位 1），地主只剩一张牌且一定比 5 大：先出单张 5 必输，出对子或 2 都能赢
+	hand := testCards(card.Rank2, card.Rank5, card.Rank5)
+	view := viewFor(1, 0, hand, []int{1, 3, 10}, rule.ParsedHand{}, 1)
+	view.Unseen = map[card.Rank]int{card.RankA: 1, card.RankK: 4, card.Rank9: 4, card.Rank8: 2}
+
+	p := NewPIMC(time.Second, 2)
+	p.Playouts = 200
+	choice := cardRanks(p.Play(view).Cards)
+
+	assert.NotEqual(t, []card.Rank{card.Rank5}, choice)
+	assert.Contains(t, [][]card.Rank{{card.Rank5, card.Rank5}, {card.Rank2}}, choice)
+}
+
+// TestPIMC_Budget makes sure the thinking time always fits inside the turn timeout.
+func TestPIMC_Budget(t *testing.T) {
+	assert.Equal(t, DefaultPIMCBudget, NewPIMC(0, 1).budget())
+	assert.Equal(t, 500*time.Millisecond, NewPIMC(500*time.Millisecond, 1).budget())
+	assert.Less(t, NewPIMC(time.Hour, 1).budget(), game.PlayerTurnTimeout)
+	assert.Positive(t, NewPIMC(0, 0).workers())
+}
+
+// TestPIMC_FullGame plays a complete game with PIMC seats on a small playout budget.
+func TestPIMC_FullGame(t *testing.T) {
+	p := NewPIMC(time.Second, 2)
+	p.Playouts = 40
+
+	g := game.NewGame()
+	g.Deal()
+	g.Bidding()
+	require.NoError(t, g.Run([]game.Agent{p, NewHeuristic(), p}))
+	_, isOver := g.CheckWinner()
+	assert.True(t, isOver)
+}
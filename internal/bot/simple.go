@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Simple 最简单的电脑玩家：有王炸、炸弹或两张 2 才叫地主，
+// 自由出牌时打最小的牌，跟牌时用最小的同类型牌压，压不住就 PASS，从不主动炸。
+type Simple struct{}
+
+// NewSimple 创建一个 Simple 电脑玩家
+func NewSimple() *Simple {
+	return &Simple{}
+}
+
+// Bid 实现 game.Agent
+func (s *Simple) Bid(view game.PlayerView) game.BidAction {
+	counts := make(map[card.Rank]int)
+	for _, c := range view.Hand {
+		counts[c.Rank]++
+	}
+	strong := counts[card.Rank2] >= 2 || (counts[card.RankBlackJoker] >= 1 && counts[card.RankRedJoker] >= 1)
+	for _, n := range counts {
+		strong = strong || n == 4
+	}
+	if !strong || len(view.ValidBids) < 2 {
+		return game.BidPass
+	}
+	return view.ValidBids[1] // 最小的非 PASS 动作
+}
+
+// Play 实现 game.Agent
+func (s *Simple) Play(view game.PlayerView) rule.ParsedHand {
+	last := view.LastPlayedHand
+	if view.FreePlay() {
+		last = rule.ParsedHand{}
+	}
+	for _, play := range rule.EnumerateLegalPlays(view.Rules, view.Hand, last) {
+		if last.IsEmpty() || play.Type == last.Type {
+			return play
+		}
+	}
+	return rule.ParsedHand{}
+}
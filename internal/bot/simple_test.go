@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCards creates distinct cards for testing, cycling suits for repeated ranks.
+func testCards(ranks ...card.Rank) []card.Card {
+	seen := make(map[card.Rank]int)
+	cards := make([]card.Card, len(ranks))
+	for i, r := range ranks {
+		suit := card.Suit(seen[r] % 4)
+		if r >= card.RankBlackJoker {
+			suit = card.Joker
+		}
+		color := card.Black
+		if suit == card.Heart || suit == card.Diamond || r == card.RankRedJoker {
+			color = card.Red
+		}
+		cards[i] = card.Card{Rank: r, Suit: suit, Color: color}
+		seen[r]++
+	}
+	return cards
+}
+
+func cardRanks(cards []card.Card) []card.Rank {
+	ranks := make([]card.Rank, len(cards))
+	for i, c := range cards {
+		ranks[i] = c.Rank
+	}
+	return ranks
+}
+
+// TestSimple_Play uses a table to test the simple bot's play choices.
+func TestSimple_Play(t *testing.T) {
+	single := func(r card.Rank) rule.ParsedHand {
+		hands, _ := rule.ParseHand(rule.Classic, testCards(r))
+		return hands[0]
+	}
+
+	testCases := []struct {
+		name     string
+		view     game.PlayerView
+		expected []card.Rank
+	}{
+		{
+			name:     "leads the smallest card",
+			view:     game.PlayerView{Seat: 0, Hand: testCards(card.RankA, card.Rank7, card.Rank4)},
+			expected: []card.Rank{card.Rank4},
+		},
+		{
+			name:     "follows with the smallest higher card",
+			view:     game.PlayerView{Seat: 1, Hand: testCards(card.Rank2, card.RankK, card.Rank3), LastPlayedHand: single(card.Rank10), LastPlayerIdx: 0},
+			expected: []card.Rank{card.RankK},
+		},
+		{
+			name:     "passes instead of bombing",
+			view:     game.PlayerView{Seat: 1, Hand: testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5), LastPlayedHand: single(card.Rank10), LastPlayerIdx: 0},
+			expected: []card.Rank{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, cardRanks(NewSimple().Play(tc.view).Cards))
+		})
+	}
+}
+
+// TestSimple_FullGame lets three simple bots play a whole game through game.Run.
+func TestSimple_FullGame(t *testing.T) {
+	g := game.NewGame()
+	g.Deal()
+	g.Bidding()
+
+	agents := []game.Agent{NewSimple(), NewSimple(), NewSimple()}
+	require.NoError(t, g.Run(agents))
+
+	winner, isOver := g.CheckWinner()
+	require.True(t, isOver)
+	assert.NotNil(t, winner)
+}
@@ -3,7 +3,6 @@ package card
 import (
 	"math/rand"
 	"strconv"
-	"time"
 	"fmt"
 )
 
@@ -26,6 +25,27 @@ type Card struct {
 	Suit  Suit
 	Rank  Rank
 	Color CardColor
+	As    Rank `json:",omitempty"` // 癞子打出时代替的点数，0 表示没有指定
+	Deck  int  `json:",omitempty"` // 使用多副牌时属于第几副，从 0 开始，用于区分花色点数都相同的牌
+}
+
+// EffectiveRank 打出时的点数：指定了代替的点数时返回它，否则是牌本身的点数
+func (c Card) EffectiveRank() Rank {
+	if c.As != 0 {
+		return c.As
+	}
+	return c.Rank
+}
+
+// Substituted 是否是代替了别的点数的癞子
+func (c Card) Substituted() bool {
+	return c.As != 0 && c.As != c.Rank
+}
+
+// Natural 去掉代替的点数，返回这张牌本身
+func (c Card) Natural() Card {
+	c.As = 0
+	return c
 }
 
 const (
@@ -151,9 +171,21 @@ func NewDeck() Deck {
 	return deck
 }
 
-func (d Deck) Shuffle() {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
-	rand.Shuffle(len(d), func(i, j int) {
+// NewDecks 创建 n 副牌混在一起的牌堆，每张牌的 Deck 记录它属于第几副
+func NewDecks(n int) Deck {
+	deck := make(Deck, 0, 54*n)
+	for i := range n {
+		for _, c := range NewDeck() {
+			c.Deck = i
+			deck = append(deck, c)
+		}
+	}
+	return deck
+}
+
+// Shuffle 使用 r 洗牌，相同种子的 r 总是洗出相同的顺序
+func (d Deck) Shuffle(r *rand.Rand) {
+	r.Shuffle(len(d), func(i, j int) {
 		d[i], d[j] = d[j], d[i]
 	})
 }
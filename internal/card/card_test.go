@@ -2,6 +2,7 @@ package card
 
 import (
 	"fmt"
+	"math/rand"
 	"strings"
 	"testing"
 	"unicode/utf8"
@@ -103,6 +104,23 @@ func TestNewDeck(t *testing.T) {
 	assert.Equal(27, colorCounts[Black], "Should have 27 Black cards (26 + Black Joker)")
 }
 
+// TestNewDecks 验证两副牌的张数，以及同样的牌可以靠 Deck 区分
+func TestNewDecks(t *testing.T) {
+	deck := NewDecks(2)
+	assert.Len(t, deck, 108)
+
+	unique := make(map[Card]bool)
+	rankCounts := make(map[Rank]int)
+	for _, c := range deck {
+		unique[c] = true
+		rankCounts[c.Rank]++
+	}
+	assert.Len(t, unique, 108, "cards from different decks must not be equal")
+	assert.Equal(t, 8, rankCounts[RankA])
+	assert.Equal(t, 2, rankCounts[RankRedJoker])
+	assert.Equal(t, NewDeck(), NewDecks(1))
+}
+
 // TestDeck_Shuffle 验证洗牌功能
 func TestDeck_Shuffle(t *testing.T) {
 	require := require.New(t) // require 在失败时会停止测试，适合前置条件
@@ -117,7 +135,7 @@ func TestDeck_Shuffle(t *testing.T) {
 	require.Equal(deck1, deck2, "Copied deck should be identical before shuffle")
 
 	// Action: 洗牌
-	deck1.Shuffle()
+	deck1.Shuffle(rand.New(rand.NewSource(1)))
 
 	// Assertion 1: 洗牌后牌的数量不变
 	assert.Len(deck1, 54, "Shuffled deck must still have 54 cards")
@@ -128,6 +146,11 @@ func TestDeck_Shuffle(t *testing.T) {
 
 	// Assertion 3: 洗牌后，牌的集合应该和原来完全一样（只是顺序不同）
 	assert.ElementsMatch(deck2, deck1, "Shuffled deck should contain the exact same cards as the original")
+
+	// Assertion 4: 相同的种子洗出相同的顺序
+	deck3 := NewDeck()
+	deck3.Shuffle(rand.New(rand.NewSource(1)))
+	assert.Equal(deck1, deck3, "Same seed should produce the same order")
 }
 
 // TestStringers 使用表驱动测试来验证所有 String() 方法的输出
@@ -267,6 +290,13 @@ func TestFindCardsInHand(t *testing.T) {
 			expectError:   true,
 			expectedCards: nil,
 		},
+		{
+			name:          "JOKER takes every joker of a two-deck hand",
+			hand:          testRuleCards(RankRedJoker, Rank3, RankBlackJoker, RankRedJoker, RankBlackJoker),
+			input:         "JOKER",
+			expectError:   false,
+			expectedCards: testRuleCards(RankRedJoker, RankBlackJoker, RankRedJoker, RankBlackJoker),
+		},
 		{
 			name:          "fail to find Rocket when one is missing",
 			hand:          testRuleCards(RankRedJoker, Rank3),
@@ -366,6 +396,18 @@ func TestRemoveCards(t *testing.T) {
 			cardsToRemove: []Card{threeOfSpades},
 			expectedHand:  []Card{},
 		},
+		{
+			name:          "remove a wildcard standing in for another rank",
+			initialHand:   []Card{threeOfSpades, kingOfSpades},
+			cardsToRemove: []Card{{Rank: RankK, Suit: Spade, As: Rank5}},
+			expectedHand:  []Card{threeOfSpades},
+		},
+		{
+			name:          "remove one of two identical cards",
+			initialHand:   []Card{threeOfSpades, threeOfSpades, fourOfClubs},
+			cardsToRemove: []Card{threeOfSpades},
+			expectedHand:  []Card{threeOfSpades, fourOfClubs},
+		},
 		{
 			name:          "remove an empty slice of cards",
 			initialHand:   []Card{threeOfSpades, fourOfClubs},
@@ -384,6 +426,54 @@ func TestRemoveCards(t *testing.T) {
 	}
 }
 
+// TestContainsCards verifies that card multiplicity is respected.
+func TestContainsCards(t *testing.T) {
+	threeOfSpades := Card{Rank: Rank3, Suit: Spade}
+	threeOfHearts := Card{Rank: Rank3, Suit: Heart}
+	kingOfSpades := Card{Rank: RankK, Suit: Spade}
+	hand := []Card{threeOfSpades, threeOfHearts, kingOfSpades}
+
+	testCases := []struct {
+		name     string
+		cards    []Card
+		expected bool
+	}{
+		{"empty selection", []Card{}, true},
+		{"exact cards in hand", []Card{threeOfHearts, kingOfSpades}, true},
+		{"card not in hand", []Card{{Rank: RankA, Suit: Club}}, false},
+		{"same rank but different suit", []Card{{Rank: RankK, Suit: Heart}}, false},
+		{"more copies than held", []Card{kingOfSpades, kingOfSpades}, false},
+		{"wildcard standing in for another rank", []Card{{Rank: RankK, Suit: Spade, As: Rank5}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, ContainsCards(hand, tc.cards))
+		})
+	}
+}
+
+func TestCountRanks(t *testing.T) {
+	cards := []Card{{Rank: Rank3, Suit: Spade}, {Rank: RankK, Suit: Heart}, {Rank: Rank3, Suit: Club}, {Rank: RankRedJoker, Suit: Joker}}
+	counts := CountRanks(cards)
+	assert.Equal(t, 2, counts[Rank3])
+	assert.Equal(t, 1, counts[RankK])
+	assert.Equal(t, 1, counts[RankRedJoker])
+	assert.Zero(t, counts[Rank4])
+
+	rebuilt := counts.Cards()
+	assert.Len(t, rebuilt, len(cards))
+	assert.Equal(t, counts, CountRanks(rebuilt))
+}
+
+func TestRemoveRanks(t *testing.T) {
+	hand := []Card{{Rank: Rank3, Suit: Spade}, {Rank: Rank3, Suit: Heart}, {Rank: RankK, Suit: Club}, {Rank: RankA, Suit: Diamond}}
+	left := RemoveRanks(hand, []Card{{Rank: Rank3}, {Rank: RankA}, {Rank: Rank9}})
+	assert.Equal(t, []Card{{Rank: Rank3, Suit: Heart}, {Rank: RankK, Suit: Club}}, left, "each rank is removed once, regardless of suit")
+	assert.Len(t, hand, 4, "the hand is not modified")
+}
+
 // FuzzRankFromChar 对 RankFromChar 函数进行模糊测试
 func FuzzRankFromChar(f *testing.F) {
 	// 添加种子语料库。这些是有效的、我们期望函数能够正确处理的输入。
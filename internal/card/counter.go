@@ -1,5 +1,7 @@
 package card
 
+import "encoding/json"
+
 // CardCounter 记牌器，记录场上还剩下哪些牌
 type CardCounter struct {
 	remainingCards map[Rank]int
@@ -7,19 +9,35 @@ type CardCounter struct {
 
 // NewCardCounter 创建并初始化一个记牌器
 func NewCardCounter() *CardCounter {
+	return NewCardCounterForDecks(1)
+}
+
+// NewCardCounterForDecks 创建使用 decks 副牌时的记牌器
+func NewCardCounterForDecks(decks int) *CardCounter {
 	counter := &CardCounter{
 		remainingCards: make(map[Rank]int, 15),
 	}
 
-	// 初始化一副完整的牌
-	// 每种点数 (3-2) 都有4张
+	// 初始化完整的牌
+	// 每副牌中每种点数 (3-2) 都有4张
 	for r := Rank3; r <= Rank2; r++ {
-		counter.remainingCards[r] = 4
+		counter.remainingCards[r] = 4 * decks
 	}
 	// 大小王各一张
-	counter.remainingCards[RankBlackJoker] = 1
-	counter.remainingCards[RankRedJoker] = 1
+	counter.remainingCards[RankBlackJoker] = decks
+	counter.remainingCards[RankRedJoker] = decks
+
+	return counter
+}
 
+// NewCardCounterForDeck 按实际使用的一副牌创建记牌器，用于去掉了部分点数的玩法
+func NewCardCounterForDeck(deck Deck) *CardCounter {
+	counter := &CardCounter{
+		remainingCards: make(map[Rank]int, 15),
+	}
+	for _, c := range deck {
+		counter.remainingCards[c.Rank]++
+	}
 	return counter
 }
 
@@ -35,3 +53,27 @@ func (cc *CardCounter) Update(playedCards []Card) {
 func (cc *CardCounter) GetRemainingCards() map[Rank]int {
 	return cc.remainingCards
 }
+
+// MarshalJSON 以 点数 -> 剩余张数 的形式保存记牌器
+func (cc *CardCounter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cc.remainingCards)
+}
+
+// UnmarshalJSON 读取 MarshalJSON 保存的记牌器
+func (cc *CardCounter) UnmarshalJSON(data []byte) error {
+	remaining := make(map[Rank]int, 15)
+	if err := json.Unmarshal(data, &remaining); err != nil {
+		return err
+	}
+	cc.remainingCards = remaining
+	return nil
+}
+
+// Restore 把收回的牌重新记入记牌器，是 Update 的逆操作
+func (cc *CardCounter) Restore(returnedCards []Card) {
+	for _, c := range returnedCards {
+		if _, ok := cc.remainingCards[c.Rank]; ok {
+			cc.remainingCards[c.Rank]++
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package card
 
 import (
+	"encoding/json"
 	"maps"
 	"testing"
 
@@ -30,6 +31,14 @@ func TestNewCardCounter(t *testing.T) {
 	assert.Len(t, counter.remainingCards, 15, "The counter should track all 15 ranks.")
 }
 
+// TestNewCardCounterForDecks 验证两副牌的记牌器
+func TestNewCardCounterForDecks(t *testing.T) {
+	counter := NewCardCounterForDecks(2)
+	assert.Equal(t, 8, counter.remainingCards[RankK])
+	assert.Equal(t, 2, counter.remainingCards[RankBlackJoker])
+	assert.Equal(t, NewCardCounter(), NewCardCounterForDecks(1))
+}
+
 // TestCardCounter_Update 测试更新记牌器的逻辑
 func TestCardCounter_Update(t *testing.T) {
 	// 为了方便，创建一个辅助函数来快速生成牌
@@ -92,6 +101,16 @@ func TestCardCounter_Update(t *testing.T) {
 
 		assert.Equal(t, expectedState, counter.remainingCards, "Updating with an empty slice should not change the counter's state.")
 	})
+
+	t.Run("Restore undoes an update", func(t *testing.T) {
+		counter := NewCardCounter()
+		playedCards := []Card{newCard(Rank9), newCard(Rank9), newCard(RankBlackJoker)}
+
+		counter.Update(playedCards)
+		counter.Restore(playedCards)
+
+		assert.Equal(t, NewCardCounter().remainingCards, counter.remainingCards, "Restoring the played cards should bring the counter back.")
+	})
 }
 
 // TestCardCounter_GetRemainingCards 测试获取剩余牌的功能
@@ -118,3 +137,19 @@ func TestCardCounter_GetRemainingCards(t *testing.T) {
 		assert.Equal(t, 99, c.remainingCards[RankK], "Modifying the returned map should also modify the internal state, as it's a reference.")
 	})
 }
+
+// TestCardCounter_JSON 测试记牌器的保存和读取
+func TestCardCounter_JSON(t *testing.T) {
+	counter := NewCardCounter()
+	counter.Update([]Card{{Rank: RankK}, {Rank: RankK}, {Rank: RankRedJoker}})
+
+	data, err := json.Marshal(counter)
+	require.NoError(t, err)
+
+	loaded := &CardCounter{}
+	require.NoError(t, json.Unmarshal(data, loaded))
+	assert.Equal(t, counter.GetRemainingCards(), loaded.GetRemainingCards())
+	assert.Equal(t, 2, loaded.GetRemainingCards()[RankK])
+
+	require.Error(t, json.Unmarshal([]byte(`[1, 2]`), loaded))
+}
@@ -9,17 +9,19 @@ import (
 // FindCardsInHand 从手牌中根据输入字符串找出对应的牌
 func FindCardsInHand(hand []Card, input string) ([]Card, error) {
 	if input == "JOKER" {
-		var black, red *Card
-		for i := range hand {
-			if hand[i].Rank == RankBlackJoker {
-				black = &hand[i]
-			}
-			if hand[i].Rank == RankRedJoker {
-				red = &hand[i]
+		// 王炸要手中所有的王，多副牌时也是如此
+		var jokers []Card
+		var black, red bool
+		for _, c := range hand {
+			switch c.Rank {
+			case RankBlackJoker:
+				jokers, black = append(jokers, c), true
+			case RankRedJoker:
+				jokers, red = append(jokers, c), true
 			}
 		}
-		if black != nil && red != nil {
-			return []Card{*black, *red}, nil
+		if black && red {
+			return jokers, nil
 		}
 		return nil, fmt.Errorf("你没有王炸")
 	}
@@ -67,13 +69,72 @@ func FindCardsInHand(hand []Card, input string) ([]Card, error) {
 	return result, nil
 }
 
-// RemoveCards 从手牌中移除指定的牌
+// RemoveCards 从手牌中移除指定的牌（按张数计算），癞子代替的点数不影响比较
 func RemoveCards(hand []Card, toRemove []Card) []Card {
+	counts := make(map[Card]int, len(toRemove))
+	for _, c := range toRemove {
+		counts[c.Natural()]++
+	}
 	var result []Card
 	for _, hCard := range hand {
-		if !slices.Contains(toRemove, hCard) {
-			result = append(result, hCard)
+		if counts[hCard.Natural()] > 0 {
+			counts[hCard.Natural()]--
+			continue
+		}
+		result = append(result, hCard)
+	}
+	return result
+}
+
+// ContainsCards 判断手牌中是否包含 cards 中的全部牌（按张数计算），癞子代替的点数不影响比较
+func ContainsCards(hand []Card, cards []Card) bool {
+	counts := make(map[Card]int, len(hand))
+	for _, c := range hand {
+		counts[c.Natural()]++
+	}
+	for _, c := range cards {
+		c = c.Natural()
+		if counts[c] == 0 {
+			return false
+		}
+		counts[c]--
+	}
+	return true
+}
+
+// RankCounts 按点数统计张数，下标为 Rank
+type RankCounts [RankRedJoker + 1]int
+
+// CountRanks 统计每个点数的张数，癞子按本身的点数计算
+func CountRanks(cards []Card) RankCounts {
+	var counts RankCounts
+	for _, c := range cards {
+		counts[c.Rank]++
+	}
+	return counts
+}
+
+// RemoveRanks 按点数从手牌中移除 cards，每张只移除一次，不在乎花色
+func RemoveRanks(hand []Card, cards []Card) []Card {
+	counts := CountRanks(cards)
+	result := make([]Card, 0, len(hand))
+	for _, c := range hand {
+		if counts[c.Rank] > 0 {
+			counts[c.Rank]--
+			continue
 		}
+		result = append(result, c)
 	}
 	return result
 }
+
+// Cards 按点数统计生成一组牌，花色无关紧要
+func (rc RankCounts) Cards() []Card {
+	var cards []Card
+	for r := Rank3; r <= RankRedJoker; r++ {
+		for range rc[r] {
+			cards = append(cards, Card{Rank: r})
+		}
+	}
+	return cards
+}
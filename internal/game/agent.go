@@ -0,0 +1,117 @@
+package game
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Agent 为一个座位做出叫地主和出牌的决定
+// 人类玩家和电脑玩家都通过这个接口驱动，它们只能看到自己座位的 PlayerView。
+type Agent interface {
+	// Bid 返回叫地主的动作，必须是 view.ValidBids 中的一个
+	Bid(view PlayerView) BidAction
+	// Play 返回要打出的牌和它的牌型，返回空的 ParsedHand 表示 PASS
+	Play(view PlayerView) rule.ParsedHand
+}
+
+// PlayerView 某个座位在当前时刻能看到的游戏信息
+type PlayerView struct {
+	Seat           int
+	Hand           []card.Card // 自己的手牌
+	Phase          Phase
+	CurrentTurn    int
+	LandlordSeat   int               // 地主座位，叫地主阶段为 -1
+	HandSizes      []int             // 每个座位剩余的手牌数
+	Unseen         map[card.Rank]int // 记牌器中除自己手牌外还没有出现过的牌
+	Played         [][]card.Card     // 每个座位已经打出的牌
+	History        []Move            // 按顺序公开的出牌记录，PASS 的 Cards 为空
+	BottomCards    []card.Card       // 底牌，地主确定后才可见
+	BaseScore      int
+	Rules          rule.RuleSet    // 这一桌的牌型规则
+	LastPlayedHand rule.ParsedHand // 上家出牌
+	LastPlayerIdx  int
+	ValidBids      []BidAction // 叫地主阶段可以做出的动作
+	Bids           []Bid       // 这一次叫地主已经做出的动作，出牌阶段也可以看到
+}
+
+// FreePlay 是否可以自由出牌
+func (v PlayerView) FreePlay() bool {
+	return v.LastPlayedHand.IsEmpty() || v.LastPlayerIdx == v.Seat
+}
+
+// IsTeammate 判断 seat 是否与自己同一阵营
+func (v PlayerView) IsTeammate(seat int) bool {
+	if seat == v.Seat {
+		return true
+	}
+	return v.LandlordSeat >= 0 && seat != v.LandlordSeat && v.Seat != v.LandlordSeat
+}
+
+// View 生成某个座位的视角
+func (g *Game) View(seat int) PlayerView {
+	view := PlayerView{
+		Seat:           seat,
+		Hand:           slices.Clone(g.Players[seat].Hand),
+		Phase:          g.Phase,
+		CurrentTurn:    g.CurrentTurn,
+		LandlordSeat:   -1,
+		HandSizes:      make([]int, len(g.Players)),
+		Played:         make([][]card.Card, len(g.Players)),
+		BaseScore:      g.BaseScore,
+		Rules:          g.Rules,
+		LastPlayedHand: g.LastPlayedHand,
+		LastPlayerIdx:  g.LastPlayerIdx,
+	}
+	for _, m := range g.Moves {
+		view.History = append(view.History, Move{Seat: m.Seat, Cards: slices.Clone(m.Cards), Hand: m.Hand})
+	}
+	view.Unseen = maps.Clone(g.CardCounter.GetRemainingCards())
+	for _, c := range view.Hand {
+		view.Unseen[c.Rank]--
+	}
+	for i, p := range g.Players {
+		view.HandSizes[i] = len(p.Hand)
+		view.Played[i] = slices.Clone(p.Played)
+		if p.IsLandlord {
+			view.LandlordSeat = i
+		}
+	}
+	if g.LandlordCardsRevealed() {
+		view.BottomCards = slices.Clone(g.LandlordCards)
+	}
+	if g.Auction != nil {
+		view.Bids = slices.Clone(g.Auction.History)
+		if g.Phase == PhaseBidding {
+			view.ValidBids = g.Auction.ValidActions()
+		}
+	}
+	return view
+}
+
+// Step 让当前座位的 Agent 做出一次决定并应用到游戏中
+func (g *Game) Step(agent Agent) error {
+	view := g.View(g.CurrentTurn)
+	if g.Phase == PhaseBidding {
+		return g.Bid(agent.Bid(view))
+	}
+	hand := agent.Play(view)
+	if hand.IsEmpty() {
+		return g.Pass()
+	}
+	return g.PlayHand(hand)
+}
+
+// Run 由 agents 驱动每个座位，直到游戏结束
+func (g *Game) Run(agents []Agent) error {
+	for {
+		if _, isOver := g.CheckWinner(); isOver && g.Phase == PhasePlaying {
+			return nil
+		}
+		if err := g.Step(agents[g.CurrentTurn]); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedAgent replays a fixed list of decisions.
+type scriptedAgent struct {
+	bids  []BidAction
+	plays [][]card.Card
+	views []PlayerView
+}
+
+func (a *scriptedAgent) Bid(view PlayerView) BidAction {
+	a.views = append(a.views, view)
+	bid := a.bids[0]
+	a.bids = a.bids[1:]
+	return bid
+}
+
+func (a *scriptedAgent) Play(view PlayerView) rule.ParsedHand {
+	a.views = append(a.views, view)
+	play := a.plays[0]
+	a.plays = a.plays[1:]
+	if len(play) == 0 {
+		return rule.ParsedHand{}
+	}
+	return mustParseHand(play)
+}
+
+// TestGame_View checks what a seat can see.
+func TestGame_View(t *testing.T) {
+	g := setupTestGame()
+	g.Players[2].IsLandlord = true
+	g.LastPlayedHand = mustParseHand(testCards(card.Rank9))
+	g.LastPlayerIdx = 2
+
+	view := g.View(0)
+
+	assert.Equal(t, 0, view.Seat)
+	assert.Equal(t, g.Players[0].Hand, view.Hand)
+	assert.Equal(t, []int{5, 5, 5}, view.HandSizes)
+	assert.Equal(t, 2, view.Unseen[card.RankK], "the two kings in hand are not unseen")
+	assert.Equal(t, 3, view.Unseen[card.Rank3])
+	assert.Equal(t, 2, view.LandlordSeat)
+	assert.Equal(t, g.LandlordCards, view.BottomCards)
+	assert.False(t, view.FreePlay())
+	assert.True(t, view.IsTeammate(1))
+	assert.False(t, view.IsTeammate(2))
+
+	// The view must not alias the player's hand.
+	view.Hand[0] = card.Card{Rank: card.RankRedJoker}
+	assert.NotEqual(t, card.RankRedJoker, g.Players[0].Hand[0].Rank)
+}
+
+// TestGame_PlayAndPass tests the typed play and pass actions.
+func TestGame_PlayAndPass(t *testing.T) {
+	g := setupTestGame()
+
+	assert.Error(t, g.Pass(), "cannot pass when leading")
+	assert.Error(t, g.Play(testCards(card.RankA)), "card not in hand")
+	assert.Error(t, g.ValidatePlay(testCards(card.Rank3, card.Rank4)), "not a valid pattern")
+	require.NoError(t, g.ValidatePlay(testCards(card.RankK, card.RankK)))
+
+	require.NoError(t, g.Play(testCards(card.RankK, card.RankK)))
+	assert.Equal(t, 1, g.CurrentTurn)
+	assert.Len(t, g.Players[0].Hand, 3)
+	assert.Equal(t, testCards(card.RankK, card.RankK), g.View(2).Played[0])
+	assert.Equal(t, []Move{{Seat: 0, Cards: testCards(card.RankK, card.RankK), Hand: mustParseHand(testCards(card.RankK, card.RankK))}}, g.View(2).History)
+
+	assert.Equal(t, []card.Card(nil), g.TimeoutMove(), "timeout passes when a hand must be beaten")
+	require.NoError(t, g.ValidatePass())
+	require.NoError(t, g.Pass())
+	require.NoError(t, g.Pass())
+	assert.True(t, g.LastPlayedHand.IsEmpty(), "two passes reset the trick")
+	assert.Equal(t, testCards(card.Rank3), g.TimeoutMove(), "timeout leads the smallest card")
+}
+
+// TestGame_Step drives every seat through scripted agents.
+func TestGame_Step(t *testing.T) {
+	g := setupTestGame()
+	g.Phase = PhaseBidding
+	g.LandlordCards = testCards(card.Rank6, card.Rank7, card.Rank8)
+	g.StartBidding(0)
+
+	agents := []Agent{
+		&scriptedAgent{bids: []BidAction{BidThree}},
+		&scriptedAgent{},
+		&scriptedAgent{},
+	}
+	require.NoError(t, g.Step(agents[0]))
+	require.Equal(t, PhasePlaying, g.Phase)
+
+	agents[0].(*scriptedAgent).plays = [][]card.Card{testCards(card.Rank3)}
+	agents[1].(*scriptedAgent).plays = [][]card.Card{nil}
+	require.NoError(t, g.Step(agents[0]))
+	require.NoError(t, g.Step(agents[1]))
+
+	assert.Equal(t, 2, g.CurrentTurn)
+	assert.Equal(t, 1, g.ConsecutivePasses)
+	seen := agents[1].(*scriptedAgent).views[0]
+	assert.Equal(t, 1, seen.Seat)
+	assert.Equal(t, card.Rank3, seen.LastPlayedHand.KeyRank)
+}
+
+// TestGame_AmbiguousPlay plays a hand that has more than one reading.
+func TestGame_AmbiguousPlay(t *testing.T) {
+	newGame := func() *Game {
+		g := setupTestGame()
+		g.Players[0].Hand = testCards(
+			card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4,
+			card.Rank5, card.Rank5, card.Rank5, card.Rank6, card.Rank6, card.Rank6, card.Rank9,
+		)
+		return g
+	}
+	cards := newGame().Players[0].Hand[:12]
+
+	g := newGame()
+	err := g.Play(cards)
+	var ambiguous *AmbiguousPlayError
+	require.ErrorAs(t, err, &ambiguous)
+	assert.ErrorIs(t, err, ErrAmbiguousPlay)
+	require.Len(t, ambiguous.Options, 3)
+	assert.Equal(t, rule.Plane, ambiguous.Options[0].Type)
+	assert.Empty(t, g.Moves, "an ambiguous play changes nothing")
+
+	// 444555666 带 333
+	choice := ambiguous.Options[1]
+	require.NoError(t, g.PlayHand(choice))
+	assert.True(t, g.LastPlayedHand.SameAs(choice))
+	assert.Equal(t, choice, g.Moves[0].Hand)
+
+	// 棋谱中写明主体，重放时按同样的牌型出牌
+	move := g.recordMove(g.Moves[0])
+	assert.Equal(t, 9, move.Body)
+	replayed := newGame()
+	require.NoError(t, replayed.replayPlay(cards, move))
+	assert.True(t, replayed.LastPlayedHand.SameAs(choice))
+
+	// 不合法的牌型不能选
+	bad := newGame()
+	assert.ErrorIs(t, bad.PlayHand(rule.ParsedHand{Type: rule.Straight, Cards: cards}), ErrInvalidHand)
+}
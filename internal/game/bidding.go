@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Phase 定义游戏所处的阶段
+type Phase int
+
+const (
+	PhaseBidding Phase = iota // 叫地主
+	PhasePlaying              // 出牌
+)
+
+// BidStyle 定义叫地主的方式
+type BidStyle int
+
+const (
+	BidStylePoints BidStyle = iota // 叫分制：1/2/3 分，分高者得
+	BidStyleRob                    // 叫抢制：叫地主/抢地主，每抢一次翻倍
+)
+
+func (s BidStyle) String() string {
+	if s == BidStyleRob {
+		return "rob"
+	}
+	return "points"
+}
+
+// ParseBidStyle 解析叫地主方式的名字，与 String 的结果对应
+func ParseBidStyle(name string) (BidStyle, error) {
+	switch name {
+	case "points":
+		return BidStylePoints, nil
+	case "rob":
+		return BidStyleRob, nil
+	default:
+		return BidStylePoints, fmt.Errorf("无法识别的叫地主方式: %s", name)
+	}
+}
+
+// BidAction 定义一次叫地主的动作
+type BidAction int
+
+const (
+	BidPass  BidAction = iota // 不叫/不抢
+	BidOne                    // 1 分
+	BidTwo                    // 2 分
+	BidThree                  // 3 分
+	BidCall                   // 叫地主
+	BidRob                    // 抢地主
+)
+
+func (a BidAction) String() string {
+	switch a {
+	case BidPass:
+		return "PASS"
+	case BidOne:
+		return "1分"
+	case BidTwo:
+		return "2分"
+	case BidThree:
+		return "3分"
+	case BidCall:
+		return "叫地主"
+	case BidRob:
+		return "抢地主"
+	default:
+		return ""
+	}
+}
+
+// Notation 返回叫地主动作在棋谱中的记法，也是键盘输入可以接受的写法
+func (a BidAction) Notation() string {
+	switch a {
+	case BidOne:
+		return "1"
+	case BidTwo:
+		return "2"
+	case BidThree:
+		return "3"
+	case BidCall:
+		return "CALL"
+	case BidRob:
+		return "ROB"
+	default:
+		return "PASS"
+	}
+}
+
+// ParseBidNotation 解析 Notation 生成的棋谱记法，和键盘输入的写法无关
+func ParseBidNotation(notation string) (BidAction, error) {
+	for a := BidPass; a <= BidRob; a++ {
+		if a.Notation() == notation {
+			return a, nil
+		}
+	}
+	return BidPass, fmt.Errorf("无法识别的叫地主记法: %s", notation)
+}
+
+// Bid 记录一次叫地主
+type Bid struct {
+	Seat   int
+	Action BidAction
+}
+
+// Auction 叫地主的状态机
+type Auction struct {
+	Style   BidStyle
+	Seats   int
+	First   int   // 第一个叫地主的座位
+	Turn    int   // 当前应该叫地主的座位
+	History []Bid // 按顺序记录的叫地主动作
+	Leader  int   // 叫分制中出价最高者，叫抢制中最后叫/抢地主的人；-1 表示无人叫
+	Score   int   // 叫分制为当前最高分，叫抢制为当前倍数
+	Caller  int   // 叫抢制中第一个叫地主的座位，-1 表示无人叫
+
+	turns      int  // 第一轮已叫的人数
+	finalRound bool // 叫抢制中，叫地主的人正在做最后一次抢地主的决定
+	done       bool
+}
+
+// NewAuction 创建一个从 first 开始叫地主的状态机
+func NewAuction(style BidStyle, first, seats int) *Auction {
+	return &Auction{
+		Style:  style,
+		Seats:  seats,
+		First:  first,
+		Turn:   first,
+		Leader: -1,
+		Caller: -1,
+	}
+}
+
+// Done 叫地主是否已结束
+func (a *Auction) Done() bool {
+	return a.done
+}
+
+// Landlord 返回最终的地主，所有人都不叫时返回 false
+func (a *Auction) Landlord() (int, bool) {
+	if !a.done || a.Leader < 0 {
+		return -1, false
+	}
+	return a.Leader, true
+}
+
+// ValidActions 返回当前座位可以做出的动作
+func (a *Auction) ValidActions() []BidAction {
+	if a.done {
+		return nil
+	}
+	actions := []BidAction{BidPass}
+	switch a.Style {
+	case BidStylePoints:
+		for b := BidOne; b <= BidThree; b++ {
+			if int(b) > a.Score {
+				actions = append(actions, b)
+			}
+		}
+	case BidStyleRob:
+		if a.Caller < 0 {
+			actions = append(actions, BidCall)
+		} else {
+			actions = append(actions, BidRob)
+		}
+	}
+	return actions
+}
+
+// Place 为当前座位叫地主，并推进到下一个座位
+func (a *Auction) Place(action BidAction) error {
+	if a.done {
+		return ErrBiddingClosed
+	}
+	if !slices.Contains(a.ValidActions(), action) {
+		return fmt.Errorf("%w: %s", ErrInvalidBid, action)
+	}
+
+	a.History = append(a.History, Bid{Seat: a.Turn, Action: action})
+	switch action {
+	case BidOne, BidTwo, BidThree:
+		a.Leader, a.Score = a.Turn, int(action)
+	case BidCall:
+		a.Leader, a.Caller, a.Score = a.Turn, a.Turn, 1
+	case BidRob:
+		a.Leader = a.Turn
+		a.Score *= 2
+	}
+
+	if a.finalRound {
+		a.done = true
+		return nil
+	}
+
+	a.turns++
+	switch {
+	case action == BidThree:
+		// 叫到 3 分直接成为地主
+		a.done = true
+	case a.turns < a.Seats:
+		a.Turn = (a.Turn + 1) % a.Seats
+	case a.Style == BidStyleRob && a.Caller >= 0 && a.Leader != a.Caller:
+		// 有人抢过地主，叫地主的人还有最后一次抢的机会
+		a.Turn = a.Caller
+		a.finalRound = true
+	default:
+		a.done = true
+	}
+	return nil
+}
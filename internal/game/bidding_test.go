@@ -0,0 +1,141 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuction_Points uses a table to test the 1/2/3-point auction.
+func TestAuction_Points(t *testing.T) {
+	testCases := []struct {
+		name             string
+		first            int
+		actions          []BidAction
+		expectedLandlord int
+		expectedScore    int
+		expectedOK       bool
+	}{
+		{"highest bid wins after one round", 0, []BidAction{BidOne, BidTwo, BidPass}, 1, 2, true},
+		{"bidding three ends immediately", 2, []BidAction{BidThree}, 2, 3, true},
+		{"single bidder wins", 1, []BidAction{BidPass, BidPass, BidOne}, 0, 1, true},
+		{"everyone passes", 0, []BidAction{BidPass, BidPass, BidPass}, -1, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := NewAuction(BidStylePoints, tc.first, 3)
+			for _, action := range tc.actions {
+				require.NoError(t, a.Place(action))
+			}
+
+			require.True(t, a.Done())
+			landlord, ok := a.Landlord()
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedLandlord, landlord)
+			assert.Equal(t, tc.expectedScore, a.Score)
+		})
+	}
+}
+
+// TestAuction_PointsRejectsLowerBid makes sure a bid must beat the current score.
+func TestAuction_PointsRejectsLowerBid(t *testing.T) {
+	a := NewAuction(BidStylePoints, 0, 3)
+	require.NoError(t, a.Place(BidTwo))
+
+	assert.Error(t, a.Place(BidOne))
+	assert.Error(t, a.Place(BidRob), "rob is not a points-style action")
+	assert.Equal(t, []BidAction{BidPass, BidThree}, a.ValidActions())
+	assert.Equal(t, 1, a.Turn, "a rejected bid should not advance the turn")
+}
+
+// TestAuction_Rob uses a table to test the call/rob auction.
+func TestAuction_Rob(t *testing.T) {
+	testCases := []struct {
+		name             string
+		actions          []BidAction
+		expectedLandlord int
+		expectedScore    int
+		expectedOK       bool
+	}{
+		{"caller keeps it when nobody robs", []BidAction{BidCall, BidPass, BidPass}, 0, 1, true},
+		{"last robber wins when caller declines", []BidAction{BidCall, BidRob, BidPass, BidPass}, 1, 2, true},
+		{"caller robs back on the final turn", []BidAction{BidCall, BidRob, BidRob, BidRob}, 0, 8, true},
+		{"late caller needs no final round", []BidAction{BidPass, BidPass, BidCall}, 2, 1, true},
+		{"everyone passes", []BidAction{BidPass, BidPass, BidPass}, -1, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			a := NewAuction(BidStyleRob, 0, 3)
+			for _, action := range tc.actions {
+				require.NoError(t, a.Place(action))
+			}
+
+			require.True(t, a.Done())
+			landlord, ok := a.Landlord()
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedLandlord, landlord)
+			assert.Equal(t, tc.expectedScore, a.Score)
+		})
+	}
+}
+
+// TestGame_Bid tests how the auction drives the game phases.
+func TestGame_Bid(t *testing.T) {
+	t.Run("landlord takes the bottom cards and leads", func(t *testing.T) {
+		g := NewGame()
+		g.Deal()
+		g.StartBidding(1)
+
+		require.False(t, g.LandlordCardsRevealed())
+		assert.ErrorIs(t, g.Play(testCards(card.Rank3)), ErrNotPlaying, "playing must be rejected during bidding")
+
+		require.NoError(t, g.Bid(BidOne))
+		assert.Equal(t, 2, g.CurrentTurn)
+		require.NoError(t, g.Bid(BidThree))
+
+		assert.Equal(t, PhasePlaying, g.Phase)
+		assert.True(t, g.LandlordCardsRevealed())
+		assert.True(t, g.Players[2].IsLandlord)
+		assert.Len(t, g.Players[2].Hand, 20)
+		assert.Equal(t, 3, g.BaseScore)
+		assert.Equal(t, 2, g.CurrentTurn)
+		assert.ErrorIs(t, g.Bid(BidPass), ErrNotBidding, "bidding is over")
+	})
+
+	t.Run("all passes redeal and rotate the first bidder", func(t *testing.T) {
+		g := NewGame()
+		g.Deal()
+		g.StartBidding(0)
+
+		for range 3 {
+			require.NoError(t, g.Bid(BidPass))
+		}
+
+		assert.Equal(t, PhaseBidding, g.Phase)
+		assert.Equal(t, 1, g.Auction.First)
+		assert.Equal(t, 1, g.CurrentTurn)
+		assert.Len(t, g.LandlordCards, 3)
+		for _, p := range g.Players {
+			assert.Len(t, p.Hand, 17)
+			assert.False(t, p.IsLandlord)
+		}
+	})
+}
+
+// TestParseBidNotation verifies every action round-trips through its record notation.
+func TestParseBidNotation(t *testing.T) {
+	for a := BidPass; a <= BidRob; a++ {
+		action, err := ParseBidNotation(a.Notation())
+		require.NoError(t, err)
+		assert.Equal(t, a, action)
+	}
+
+	_, err := ParseBidNotation("叫")
+	assert.Error(t, err, "keyboard aliases are not record notation")
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Event 游戏在状态变化时发布的事件，具体类型见下面的结构体
+type Event interface {
+	event()
+}
+
+// Dealt 发完牌，所有人都不叫重新发牌时 Redeal 为 true
+type Dealt struct {
+	Redeal bool
+	Wild   card.Rank // 癞子玩法中本局的癞子点数，否则为 0
+}
+
+// BidPlaced 某个座位叫了地主
+type BidPlaced struct {
+	Seat   int
+	Action BidAction
+}
+
+// LandlordChosen 地主确定，底牌亮出
+type LandlordChosen struct {
+	Seat        int
+	BaseScore   int
+	BottomCards []card.Card
+}
+
+// CardsPlayed 某个座位出了牌
+type CardsPlayed struct {
+	Seat int
+	Hand rule.ParsedHand
+}
+
+// Passed 某个座位选择不出
+type Passed struct {
+	Seat int
+}
+
+// TrickReset 其他人都不要，由 Leader 开始新的一轮
+type TrickReset struct {
+	Leader int
+}
+
+// BombPlayed 打出了炸弹或王炸，倍数翻倍，紧跟在对应的 CardsPlayed 之后发布
+type BombPlayed struct {
+	Seat       int
+	Hand       rule.ParsedHand
+	Multiplier int // 翻倍之后的倍数
+}
+
+// GameOver 有人出完了牌
+type GameOver struct {
+	Winner       int
+	LandlordWins bool
+}
+
+// MoveUndone 练习模式中撤销了某个座位的一手，Cards 为空表示撤销的是 PASS
+type MoveUndone struct {
+	Seat  int
+	Cards []card.Card
+}
+
+func (Dealt) event()          {}
+func (BidPlaced) event()      {}
+func (LandlordChosen) event() {}
+func (CardsPlayed) event()    {}
+func (Passed) event()         {}
+func (TrickReset) event()     {}
+func (BombPlayed) event()     {}
+func (GameOver) event()       {}
+func (MoveUndone) event()     {}
+
+// Subscribe 注册一个回调，每个事件发布时同步调用，返回取消订阅的函数
+func (g *Game) Subscribe(fn func(Event)) (unsubscribe func()) {
+	if g.subscribers == nil {
+		g.subscribers = make(map[int]func(Event))
+	}
+	id := g.nextSubscriber
+	g.nextSubscriber++
+	g.subscribers[id] = fn
+	return func() { delete(g.subscribers, id) }
+}
+
+// Events 以 channel 的形式订阅事件，buffer 为 channel 的缓冲大小
+// 发布事件时会等待 channel 有空位，消费者需要及时读取；取消订阅时关闭 channel。
+func (g *Game) Events(buffer int) (events <-chan Event, unsubscribe func()) {
+	ch := make(chan Event, buffer)
+	cancel := g.Subscribe(func(e Event) { ch <- e })
+	return ch, func() {
+		cancel()
+		close(ch)
+	}
+}
+
+// publish 按订阅的顺序把事件交给所有订阅者
+func (g *Game) publish(e Event) {
+	for id := range g.nextSubscriber {
+		if fn, ok := g.subscribers[id]; ok {
+			fn(e)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mustParseHand 解析测试用的牌型，返回默认的解释
+func mustParseHand(cards []card.Card) rule.ParsedHand {
+	hands, err := rule.ParseHand(rule.Classic, cards)
+	if err != nil {
+		panic(err)
+	}
+	return hands[0]
+}
+
+func TestGame_Events(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		setup    func(g *Game)
+		actions  func(t *testing.T, g *Game)
+		expected []Event
+	}{
+		{
+			name: "bids then landlord chosen",
+			setup: func(g *Game) {
+				g.Deal()
+				g.StartBidding(0)
+			},
+			actions: func(t *testing.T, g *Game) {
+				require.NoError(t, g.Bid(BidPass))
+				require.NoError(t, g.Bid(BidTwo))
+				require.NoError(t, g.Bid(BidPass))
+			},
+			expected: []Event{
+				BidPlaced{Seat: 0, Action: BidPass},
+				BidPlaced{Seat: 1, Action: BidTwo},
+				BidPlaced{Seat: 2, Action: BidPass},
+				LandlordChosen{Seat: 1, BaseScore: 2},
+			},
+		},
+		{
+			name: "everyone passes and cards are redealt",
+			setup: func(g *Game) {
+				g.Deal()
+				g.StartBidding(0)
+			},
+			actions: func(t *testing.T, g *Game) {
+				for range 3 {
+					require.NoError(t, g.Bid(BidPass))
+				}
+			},
+			expected: []Event{
+				BidPlaced{Seat: 0, Action: BidPass},
+				BidPlaced{Seat: 1, Action: BidPass},
+				BidPlaced{Seat: 2, Action: BidPass},
+				Dealt{Redeal: true},
+			},
+		},
+		{
+			name: "two passes reset the trick",
+			actions: func(t *testing.T, g *Game) {
+				require.NoError(t, g.Play(testCards(card.Rank3)))
+				require.NoError(t, g.Pass())
+				require.NoError(t, g.Pass())
+			},
+			expected: []Event{
+				CardsPlayed{Seat: 0, Hand: mustParseHand(testCards(card.Rank3))},
+				Passed{Seat: 1},
+				Passed{Seat: 2},
+				TrickReset{Leader: 0},
+			},
+		},
+		{
+			name: "bomb doubles and ends the game",
+			setup: func(g *Game) {
+				g.Players[0].IsLandlord = true
+				g.Players[0].Hand = testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5)
+			},
+			actions: func(t *testing.T, g *Game) {
+				require.NoError(t, g.Play(testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5)))
+			},
+			expected: []Event{
+				CardsPlayed{Seat: 0, Hand: mustParseHand(testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5))},
+				BombPlayed{Seat: 0, Hand: mustParseHand(testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5)), Multiplier: 2},
+				GameOver{Winner: 0, LandlordWins: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := setupTestGame()
+			if tc.setup != nil {
+				tc.setup(g)
+			}
+			var events []Event
+			g.Subscribe(func(e Event) {
+				// 底牌是随机的，只比较其余字段
+				if lc, ok := e.(LandlordChosen); ok {
+					assert.Len(t, lc.BottomCards, 3)
+					lc.BottomCards = nil
+					e = lc
+				}
+				events = append(events, e)
+			})
+			tc.actions(t, g)
+			assert.Equal(t, tc.expected, events)
+		})
+	}
+}
+
+func TestGame_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	g := setupTestGame()
+	var first, second int
+	unsubscribe := g.Subscribe(func(Event) { first++ })
+	g.Subscribe(func(Event) { second++ })
+
+	require.NoError(t, g.Play(testCards(card.Rank3)))
+	unsubscribe()
+	require.NoError(t, g.Pass())
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
+
+func TestGame_EventsChannel(t *testing.T) {
+	t.Parallel()
+
+	g := setupTestGame()
+	events, unsubscribe := g.Events(4)
+	require.NoError(t, g.Play(testCards(card.Rank3)))
+	require.NoError(t, g.Pass())
+	unsubscribe()
+
+	var received []Event
+	for e := range events {
+		received = append(received, e)
+	}
+	require.Len(t, received, 2)
+	assert.IsType(t, CardsPlayed{}, received[0])
+	assert.Equal(t, Passed{Seat: 1}, received[1])
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,167 +16,409 @@ const (
 	PlayerTurnTimeout = 30 * time.Second
 )
 
+// 出牌和叫地主可能返回的错误，可以用 errors.Is 判断
+var (
+	ErrNotBidding    = errors.New("现在不是叫地主阶段")
+	ErrNotPlaying    = errors.New("叫地主尚未结束，不能出牌")
+	ErrGameOver      = errors.New("游戏已经结束")
+	ErrCardsNotHeld  = errors.New("手牌中没有这些牌")
+	ErrInvalidHand   = errors.New("无效的牌型")
+	ErrCannotBeat    = errors.New("你的牌没有大过上家")
+	ErrMustPlay      = errors.New("轮到你出牌，不能PASS")
+	ErrInvalidBid    = errors.New("不能这样叫地主")
+	ErrBiddingClosed = errors.New("叫地主已经结束")
+	ErrAmbiguousPlay = errors.New("这手牌有多种牌型")
+)
+
+// AmbiguousPlayError 打出的牌有多种合法的牌型时 Play 返回的错误，需要用 PlayHand 选择其中一种
+// 可以用 errors.Is(err, ErrAmbiguousPlay) 判断。
+type AmbiguousPlayError struct {
+	Options []rule.ParsedHand // 所有合法的牌型，第一个是默认的解释
+}
+
+func (e *AmbiguousPlayError) Error() string {
+	names := make([]string, len(e.Options))
+	for i, h := range e.Options {
+		names[i] = h.Type.String()
+	}
+	return fmt.Sprintf("%v，可以是: %s", ErrAmbiguousPlay, strings.Join(names, "、"))
+}
+
+func (e *AmbiguousPlayError) Unwrap() error {
+	return ErrAmbiguousPlay
+}
+
 // Game 定义游戏状态
 type Game struct {
-	Players              [3]*Player
+	Players              []*Player
 	Deck                 card.Deck
 	LandlordCards        []card.Card     // 地主手牌
+	DeadCards            []card.Card     // 发剩下不用的牌，例如两人玩法中的 9 张，对局中谁也看不到
+	Phase                Phase           // 当前阶段
+	BidStyle             BidStyle        // 叫地主方式
+	Rules                rule.RuleSet    // 牌型规则
+	Auction              *Auction        // 叫地主状态，调用 Bidding 后创建
+	BaseScore            int             // 底分，由叫地主决定
 	CurrentTurn          int             // 当前出牌玩家
 	LastPlayedHand       rule.ParsedHand // 上家出牌
 	LastPlayerIdx        int             // 上家
 	ConsecutivePasses    int
 	CardCounter          *card.CardCounter
 	CanCurrentPlayerPlay bool
+	Seed                 int64  // 随机种子，相同的种子总是发出相同的牌
+	Bids                 []Bid  // 叫地主记录，包括重新发牌之前的
+	Moves                []Move // 出牌记录
+	Practice             bool   // 练习模式，允许悔棋
+
+	rng            *rand.Rand
+	firstBidder    int    // 由种子决定的第一个叫地主的玩家
+	redeals        int    // 所有人都不叫而重新发牌的次数
+	redo           []Move // 悔棋撤销的出牌，最后撤销的在最后
+	subscribers    map[int]func(Event)
+	nextSubscriber int
 }
 
-// NewGame 初始化一个新游戏
+// NewGame 使用基于当前时间的随机种子初始化一个新游戏
 func NewGame() *Game {
-	players := [3]*Player{
-		{Name: "Player 1 (你)"},
-		{Name: "Player 2"},
-		{Name: "Player 3"},
+	return NewGameWithSeed(time.Now().UnixNano())
+}
+
+// NewGameWithSeed 使用指定的随机种子初始化一个新游戏，洗牌和选择先叫地主的玩家都由种子决定
+func NewGameWithSeed(seed int64) *Game {
+	return NewGameWithRules(seed, rule.Classic)
+}
+
+// NewGameWithRules 使用指定的随机种子和规则初始化一个新游戏，人数和用几副牌由规则决定
+func NewGameWithRules(seed int64, rules rule.RuleSet) *Game {
+	players := make([]*Player, rules.PlayerCount())
+	for i := range players {
+		players[i] = &Player{Name: fmt.Sprintf("Player %d", i+1)}
 	}
-	deck := card.NewDeck()
-	deck.Shuffle()
+	players[0].Name += " (你)"
+	rng := rand.New(rand.NewSource(seed))
+	deck := rules.NewDeck()
+	deck.Shuffle(rng)
+	first := rng.Intn(len(players))
 
 	return &Game{
 		Players:              players,
 		Deck:                 deck,
-		CardCounter:          card.NewCardCounter(),
+		Rules:                rules,
+		CardCounter:          card.NewCardCounterForDeck(deck),
 		CanCurrentPlayerPlay: true, // 游戏开始时，第一个玩家总是有牌可出
+		Seed:                 seed,
+		rng:                  rng,
+		firstBidder:          first,
 	}
 }
 
 // Deal 发牌
 func (g *Game) Deal() {
-	for range 17 {
+	for range g.Rules.HandSize() {
 		for _, p := range g.Players {
 			p.Hand = append(p.Hand, g.Deck[0])
 			g.Deck = g.Deck[1:]
 		}
 	}
-	g.LandlordCards = g.Deck
+	bottom := g.Rules.BottomCount()
+	g.LandlordCards, g.DeadCards = g.Deck[:bottom:bottom], nil
+	if len(g.Deck) > bottom {
+		g.DeadCards = g.Deck[bottom:]
+	}
 	for _, p := range g.Players {
 		p.SortHand()
 	}
+	if g.Rules.WildCards {
+		g.Rules.Wild = drawWild(g.rng)
+	}
+	g.publish(Dealt{Redeal: len(g.Bids) > 0, Wild: g.Rules.Wild})
+}
+
+// drawWild 癞子玩法发牌后随机翻出癞子的点数，大小王不会成为癞子
+func drawWild(rng *rand.Rand) card.Rank {
+	return card.Rank3 + card.Rank(rng.Intn(int(card.Rank2-card.Rank3)+1))
 }
 
-// Bidding 叫地主（此处为简化版，随机选择一个）
+// Bidding 开始叫地主，第一个叫地主的玩家由种子随机决定
 func (g *Game) Bidding() {
-	landlordIdx := rand.Intn(3)
+	g.StartBidding(g.firstBidder)
+}
+
+// StartBidding 从 first 开始叫地主
+func (g *Game) StartBidding(first int) {
+	g.Phase = PhaseBidding
+	g.Auction = NewAuction(g.BidStyle, first, len(g.Players))
+	g.CurrentTurn = first
+}
+
+// Bid 处理当前玩家的一次叫地主操作
+func (g *Game) Bid(action BidAction) error {
+	if g.Phase != PhaseBidding || g.Auction == nil {
+		return ErrNotBidding
+	}
+	seat := g.Auction.Turn
+	if err := g.Auction.Place(action); err != nil {
+		return err
+	}
+	g.Bids = append(g.Bids, Bid{Seat: seat, Action: action})
+	g.publish(BidPlaced{Seat: seat, Action: action})
+	if !g.Auction.Done() {
+		g.CurrentTurn = g.Auction.Turn
+		return nil
+	}
+
+	landlordIdx, ok := g.Auction.Landlord()
+	if !ok {
+		// 所有人都不叫，重新发牌，由下一位玩家先叫
+		first := (g.Auction.First + 1) % len(g.Players)
+		g.redeal()
+		g.StartBidding(first)
+		return nil
+	}
+	g.setLandlord(landlordIdx, g.Auction.Score)
+	return nil
+}
+
+// setLandlord 确定地主，亮出底牌并进入出牌阶段
+func (g *Game) setLandlord(landlordIdx, baseScore int) {
 	g.Players[landlordIdx].IsLandlord = true
 	g.Players[landlordIdx].Hand = append(g.Players[landlordIdx].Hand, g.LandlordCards...)
 	g.Players[landlordIdx].SortHand()
 
+	g.BaseScore = baseScore
+	g.Phase = PhasePlaying
 	g.CurrentTurn = landlordIdx
 	g.LastPlayerIdx = landlordIdx
+	g.publish(LandlordChosen{Seat: landlordIdx, BaseScore: baseScore, BottomCards: g.LandlordCards})
 }
 
-// PlayTurn 处理玩家的一次出牌操作
-func (g *Game) PlayTurn(input string) error {
-	currentPlayer := g.Players[g.CurrentTurn]
+// redeal 收回所有手牌，重新洗牌发牌
+func (g *Game) redeal() {
+	for _, p := range g.Players {
+		p.Hand = nil
+	}
+	g.Deck = g.Rules.NewDeck()
+	g.Deck.Shuffle(g.rng)
+	g.redeals++
+	g.Deal()
+}
 
-	// 1. 预处理输入，处理超时情况
-	processedInput := g.preprocessInput(input, currentPlayer)
+// LandlordCardsRevealed 底牌是否已经亮出（地主确定之前底牌不可见）
+func (g *Game) LandlordCardsRevealed() bool {
+	return g.Phase != PhaseBidding
+}
 
-	// 2. 根据输入，分派给不同的处理函数
-	var err error
-	if strings.ToUpper(strings.TrimSpace(processedInput)) == "PASS" {
-		err = g.handlePass()
-	} else {
-		err = g.handlePlay(currentPlayer, processedInput)
+// Play 当前玩家打出指定的牌
+// 这手牌有多种合法的牌型时返回 *AmbiguousPlayError，不改变游戏状态。
+func (g *Game) Play(cards []card.Card) error {
+	options, err := g.Interpretations(cards)
+	if err != nil {
+		return err
 	}
+	if len(options) > 1 {
+		return &AmbiguousPlayError{Options: options}
+	}
+	g.playHand(options[0])
+	return nil
+}
 
-	// 3. 如果处理过程中出现错误，立即返回
+// PlayHand 当前玩家按 hand 的牌型打出 hand.Cards，用于在一手牌有多种牌型时做出选择
+func (g *Game) PlayHand(hand rule.ParsedHand) error {
+	options, err := g.Interpretations(hand.Cards)
 	if err != nil {
 		return err
 	}
+	i := slices.IndexFunc(options, hand.SameAs)
+	if i < 0 {
+		return fmt.Errorf("%w: 这手牌不能作为%s打出", ErrInvalidHand, hand.Type)
+	}
+	g.playHand(options[i])
+	return nil
+}
 
-	// 4. 如果回合成功，则推进到下一回合，并更新状态
-	g.advanceToNextTurn()
+// Interpretations 返回当前玩家打出 cards 时所有合法的牌型，不改变游戏状态
+// 跟牌时只包括能大过上家的牌型；有多种时第一个是默认的解释。
+func (g *Game) Interpretations(cards []card.Card) ([]rule.ParsedHand, error) {
+	if err := g.checkPlaying(); err != nil {
+		return nil, err
+	}
+	return g.checkPlay(g.Players[g.CurrentTurn], cards)
+}
 
-	// 5. 如果游戏已经结束，更新状态
-	if len(currentPlayer.Hand) == 0 {
-		g.CanCurrentPlayerPlay = false
+// playHand 打出已经校验过的一手牌，推进回合并发布事件
+func (g *Game) playHand(hand rule.ParsedHand) {
+	seat, currentPlayer := g.CurrentTurn, g.Players[g.CurrentTurn]
+
+	g.LastPlayedHand = hand
+	g.LastPlayerIdx = seat
+	g.ConsecutivePasses = 0
+	g.CardCounter.Update(hand.Cards)
+	currentPlayer.Hand = card.RemoveCards(currentPlayer.Hand, hand.Cards)
+	currentPlayer.Played = append(currentPlayer.Played, hand.Cards...)
+	g.Moves = append(g.Moves, Move{Seat: seat, Cards: hand.Cards, Hand: hand})
+	g.finishTurn(currentPlayer)
+	g.redo = nil
+
+	g.publish(CardsPlayed{Seat: seat, Hand: hand})
+	if hand.Type.IsBomb() {
+		g.publish(BombPlayed{Seat: seat, Hand: hand, Multiplier: g.Multiplier()})
+	}
+	if winner, isOver := g.CheckWinner(); isOver {
+		g.publish(GameOver{Winner: seat, LandlordWins: winner.IsLandlord})
+	}
+}
+
+// Pass 当前玩家选择不出
+func (g *Game) Pass() error {
+	if err := g.checkPlaying(); err != nil {
+		return err
 	}
+	seat, currentPlayer := g.CurrentTurn, g.Players[g.CurrentTurn]
+	if err := g.handlePass(); err != nil {
+		return err
+	}
+	g.finishTurn(currentPlayer)
+	g.redo = nil
 
+	g.publish(Passed{Seat: seat})
+	if g.trickOver() {
+		g.publish(TrickReset{Leader: g.LastPlayerIdx})
+	}
 	return nil
 }
 
-// preprocessInput 负责处理超时逻辑，返回一个确定的指令 ("PASS" 或出牌字符串)
-func (g *Game) preprocessInput(input string, currentPlayer *Player) string {
-	isTimeout := input == ""
-	if !isTimeout {
-		return input
+// Timeout 当前玩家超时：叫地主阶段不叫，出牌阶段按 TimeoutMove 托管出牌
+func (g *Game) Timeout() error {
+	if g.Phase == PhaseBidding {
+		return g.Bid(BidPass)
 	}
+	if cards := g.TimeoutMove(); len(cards) > 0 {
+		return g.Play(cards)
+	}
+	return g.Pass()
+}
+
+// ValidatePlay 检查当前玩家能否打出指定的牌，不改变游戏状态
+func (g *Game) ValidatePlay(cards []card.Card) error {
+	_, err := g.Interpretations(cards)
+	return err
+}
+
+// ValidatePass 检查当前玩家能否 PASS，不改变游戏状态
+func (g *Game) ValidatePass() error {
+	if err := g.checkPlaying(); err != nil {
+		return err
+	}
+	if !g.passAllowed() {
+		return ErrMustPlay
+	}
+	return nil
+}
+
+// TimeoutMove 超时托管的出牌：自由出牌时打出最小的单牌，否则 PASS（返回 nil）
+func (g *Game) TimeoutMove() []card.Card {
+	return g.timeoutMove(g.Players[g.CurrentTurn])
+}
 
+func (g *Game) timeoutMove(currentPlayer *Player) []card.Card {
 	// 如果是轮到你自由出牌，不能pass，自动打出最小的单牌
-	if g.LastPlayerIdx == g.CurrentTurn || g.LastPlayedHand.IsEmpty() {
-		minCard := currentPlayer.Hand[len(currentPlayer.Hand)-1] // 手牌已排序
-		return minCard.Rank.String()
+	if g.isFreePlay() {
+		return []card.Card{currentPlayer.Hand[len(currentPlayer.Hand)-1]} // 手牌已排序
 	}
+	return nil
+}
+
+// checkPlaying 检查当前是否处于可以出牌的阶段
+func (g *Game) checkPlaying() error {
+	if g.Phase != PhasePlaying {
+		return ErrNotPlaying
+	}
+	if _, isOver := g.CheckWinner(); isOver {
+		return ErrGameOver
+	}
+	return nil
+}
+
+// isFreePlay 当前玩家是否可以自由出牌
+func (g *Game) isFreePlay() bool {
+	return g.LastPlayerIdx == g.CurrentTurn || g.LastPlayedHand.IsEmpty()
+}
+
+// passAllowed 当前玩家是否可以 PASS（一轮的第一手牌不能 PASS）
+func (g *Game) passAllowed() bool {
+	return g.LastPlayerIdx != g.CurrentTurn && !g.trickOver()
+}
+
+// trickOver 出牌的人之后其他人都 PASS 了，这一轮结束
+func (g *Game) trickOver() bool {
+	return g.ConsecutivePasses == len(g.Players)-1
+}
+
+// finishTurn 回合成功后推进到下一回合，并更新状态
+func (g *Game) finishTurn(currentPlayer *Player) {
+	g.advanceToNextTurn()
 
-	// 否则自动PASS
-	return "PASS"
+	// 如果游戏已经结束，更新状态
+	if len(currentPlayer.Hand) == 0 {
+		g.CanCurrentPlayerPlay = false
+	}
 }
 
 // handlePass 专门处理玩家选择 PASS 的逻辑
 func (g *Game) handlePass() error {
-	if g.LastPlayerIdx == g.CurrentTurn || g.ConsecutivePasses == 2 {
-		return errors.New("轮到你出牌，不能PASS")
+	if !g.passAllowed() {
+		return ErrMustPlay
 	}
+	g.Moves = append(g.Moves, Move{Seat: g.CurrentTurn})
 	g.ConsecutivePasses++
-	if g.ConsecutivePasses == 2 {
-		// 如果连续两人PASS，则开启新的一轮
+	if g.trickOver() {
+		// 如果其他人都 PASS，则开启新的一轮
 		g.LastPlayedHand = rule.ParsedHand{}
-		g.LastPlayerIdx = (g.CurrentTurn + 1) % 3 // 新一轮由下家开始
+		g.LastPlayerIdx = (g.CurrentTurn + 1) % len(g.Players) // 新一轮由下家开始
 	}
 	return nil
 }
 
-// handlePlay 专门处理玩家出牌的逻辑
-func (g *Game) handlePlay(currentPlayer *Player, input string) error {
-	cardsToPlay, err := card.FindCardsInHand(currentPlayer.Hand, strings.ToUpper(input))
-	if err != nil {
-		return fmt.Errorf("出牌无效: %w", err)
+// checkPlay 校验出牌是否合法，返回所有合法的牌型
+func (g *Game) checkPlay(currentPlayer *Player, cardsToPlay []card.Card) ([]rule.ParsedHand, error) {
+	if !card.ContainsCards(currentPlayer.Hand, cardsToPlay) {
+		return nil, fmt.Errorf("出牌无效: %w", ErrCardsNotHeld)
 	}
 
-	handToPlay, err := rule.ParseHand(cardsToPlay)
+	hands, err := rule.ParseHand(g.Rules, cardsToPlay)
 	if err != nil {
-		return fmt.Errorf("无效的牌型: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHand, err)
 	}
 
-	isNewRound := g.LastPlayerIdx == g.CurrentTurn || g.LastPlayedHand.IsEmpty() || g.ConsecutivePasses == 2
-	if isNewRound || rule.CanBeat(handToPlay, g.LastPlayedHand) {
-		// 出牌成功，更新游戏状态
-		g.LastPlayedHand = handToPlay
-		g.LastPlayerIdx = g.CurrentTurn
-		g.ConsecutivePasses = 0
-		g.CardCounter.Update(cardsToPlay)
-		currentPlayer.Hand = card.RemoveCards(currentPlayer.Hand, cardsToPlay)
-
-		return nil
+	isNewRound := g.isFreePlay() || g.trickOver()
+	if isNewRound {
+		return hands, nil
 	}
-
-	return errors.New("你的牌没有大过上家")
+	hands = slices.DeleteFunc(hands, func(h rule.ParsedHand) bool {
+		return !rule.CanBeat(g.Rules, h, g.LastPlayedHand)
+	})
+	if len(hands) == 0 {
+		return nil, ErrCannotBeat
+	}
+	return hands, nil
 }
 
 // advanceToNextTurn 推进回合，并为下一个玩家设置状态
 func (g *Game) advanceToNextTurn() {
 	// 1. 将回合交给下一个玩家
-	g.CurrentTurn = (g.CurrentTurn + 1) % 3
+	g.CurrentTurn = (g.CurrentTurn + 1) % len(g.Players)
 
-	// 2. 获取下一个玩家
-	nextPlayer := g.Players[g.CurrentTurn]
+	// 2. 判断下一个玩家是否有牌可打
+	g.updateCanPlay()
+}
 
-	// 3. 判断下一个玩家是否可以自由出牌
-	isFreePlay := g.LastPlayedHand.IsEmpty() || g.LastPlayerIdx == g.CurrentTurn
-	if isFreePlay {
+// updateCanPlay 重新判断当前玩家是否有牌可打：自由出牌时总是可以，否则要压得住上家
+func (g *Game) updateCanPlay() {
+	if g.isFreePlay() {
 		g.CanCurrentPlayerPlay = true
-	} else {
-		// 否则，检查他是否有牌可打
-		g.CanCurrentPlayerPlay = rule.CanBeatWithHand(nextPlayer.Hand, g.LastPlayedHand)
+		return
 	}
+	g.CanCurrentPlayerPlay = rule.CanBeatWithHand(g.Rules, g.Players[g.CurrentTurn].Hand, g.LastPlayedHand)
 }
 
 // CheckWinner 检查是否有玩家获胜
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/palemoky/fight-the-landlord-go/internal/card"
@@ -28,59 +29,64 @@ func setupTestGame() *Game {
 	for _, p := range g.Players {
 		p.SortHand()
 	}
+	g.Phase = PhasePlaying
 	g.CurrentTurn = 0
 	g.LastPlayerIdx = 0
 	return g
 }
 
-// TestPreprocessInput uses a table to test all timeout and input scenarios.
-func TestPreprocessInput(t *testing.T) {
+// TestGame_Timeout uses a table to test all timeout scenarios.
+func TestGame_Timeout(t *testing.T) {
 	testCases := []struct {
-		name           string
-		setupGame      func(g *Game) *Player // Setup game state and return the current player
-		initialInput   string
-		expectedOutput string
+		name        string
+		setupGame   func(g *Game)
+		assertState func(t *testing.T, g *Game)
 	}{
 		{
-			name: "with normal input string",
-			setupGame: func(g *Game) *Player {
-				return g.Players[0]
-			},
-			initialInput:   "KK",
-			expectedOutput: "KK",
-		},
-		{
-			name: "with timeout during free play",
-			setupGame: func(g *Game) *Player {
+			name: "timeout during free play leads the smallest card",
+			setupGame: func(g *Game) {
 				g.LastPlayedHand = rule.ParsedHand{} // New round
-				player := g.Players[0]
-				player.Hand = testCards(card.Rank5, card.Rank4, card.Rank3) // Smallest is 3
-				player.SortHand()
-				return player
+				g.Players[0].Hand = testCards(card.Rank5, card.Rank4, card.Rank3)
+				g.Players[0].SortHand()
+			},
+			assertState: func(t *testing.T, g *Game) {
+				assert.Equal(t, card.Rank3, g.LastPlayedHand.KeyRank)
+				assert.Len(t, g.Players[0].Hand, 2)
 			},
-			initialInput:   "", // Timeout
-			expectedOutput: "3",
 		},
 		{
-			name: "with timeout when must beat a hand",
-			setupGame: func(g *Game) *Player {
-				g.LastPlayedHand, _ = rule.ParseHand(testCards(card.Rank6))
+			name: "timeout when must beat a hand passes",
+			setupGame: func(g *Game) {
+				g.LastPlayedHand = mustParseHand(testCards(card.Rank6))
 				g.CurrentTurn = 1
 				g.LastPlayerIdx = 0
-				return g.Players[1]
 			},
-			initialInput:   "", // Timeout
-			expectedOutput: "PASS",
+			assertState: func(t *testing.T, g *Game) {
+				assert.Equal(t, 1, g.ConsecutivePasses)
+				assert.Equal(t, 2, g.CurrentTurn)
+			},
+		},
+		{
+			name: "timeout during bidding does not bid",
+			setupGame: func(g *Game) {
+				g.LandlordCards = testCards(card.Rank6, card.Rank7, card.Rank8)
+				g.StartBidding(0)
+			},
+			assertState: func(t *testing.T, g *Game) {
+				require.Len(t, g.Bids, 1)
+				assert.Equal(t, Bid{Seat: 0, Action: BidPass}, g.Bids[0])
+			},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 			g := setupTestGame()
-			player := tc.setupGame(g)
+			tc.setupGame(g)
 
-			processed := g.preprocessInput(tc.initialInput, player)
-			assert.Equal(t, tc.expectedOutput, processed)
+			require.NoError(t, g.Timeout())
+			tc.assertState(t, g)
 		})
 	}
 }
@@ -121,7 +127,7 @@ func TestHandlePass(t *testing.T) {
 				g.CurrentTurn = 2
 				g.LastPlayerIdx = 0
 				g.ConsecutivePasses = 1
-				g.LastPlayedHand, _ = rule.ParseHand(testCards(card.RankK))
+				g.LastPlayedHand = mustParseHand(testCards(card.RankK))
 			},
 			expectError: false,
 			assertState: func(t *testing.T, g *Game) {
@@ -149,69 +155,60 @@ func TestHandlePass(t *testing.T) {
 	}
 }
 
-// TestHandlePlay uses a table to test play logic.
-func TestHandlePlay(t *testing.T) {
+// TestPlayCards uses a table to test play logic.
+func TestPlayCards(t *testing.T) {
 	testCases := []struct {
-		name        string
-		setupGame   func(g *Game) *Player
-		input       string
-		expectError bool
-		assertState func(t *testing.T, g *Game)
+		name          string
+		setupGame     func(g *Game)
+		cards         []card.Card
+		expectedError error
+		assertState   func(t *testing.T, g *Game)
 	}{
 		{
 			name: "valid play on a new round",
-			setupGame: func(g *Game) *Player {
-				player := g.Players[0]
-				player.Hand = testCards(card.RankK, card.RankK)
-				return player
+			setupGame: func(g *Game) {
+				g.Players[0].Hand = testCards(card.RankK, card.RankK)
 			},
-			input:       "KK",
-			expectError: false,
+			cards: testCards(card.RankK, card.RankK),
 			assertState: func(t *testing.T, g *Game) {
 				assert.Len(t, g.Players[0].Hand, 0)
-				assert.Equal(t, g.CurrentTurn, g.LastPlayerIdx)
+				assert.Equal(t, 0, g.LastPlayerIdx)
 				assert.Equal(t, rule.Pair, g.LastPlayedHand.Type)
 			},
 		},
 		{
 			name: "valid beat of a previous hand",
-			setupGame: func(g *Game) *Player {
-				g.LastPlayedHand, _ = rule.ParseHand(testCards(card.Rank10))
+			setupGame: func(g *Game) {
+				g.LastPlayedHand = mustParseHand(testCards(card.Rank10))
 				g.LastPlayerIdx = 0
-				player := g.Players[1]
-				player.Hand = testCards(card.RankA)
-				return player
+				g.CurrentTurn = 1
+				g.Players[1].Hand = testCards(card.RankA)
 			},
-			input:       "A",
-			expectError: false,
+			cards: testCards(card.RankA),
 			assertState: func(t *testing.T, g *Game) {
 				assert.Equal(t, card.RankA, g.LastPlayedHand.KeyRank)
 			},
 		},
 		{
 			name: "invalid beat (weaker hand)",
-			setupGame: func(g *Game) *Player {
-				g.LastPlayedHand, _ = rule.ParseHand(testCards(card.RankQ))
+			setupGame: func(g *Game) {
+				g.LastPlayedHand = mustParseHand(testCards(card.RankQ))
 				g.LastPlayerIdx = 0
 				g.CurrentTurn = 1
-				player := g.Players[1]
-				player.Hand = testCards(card.Rank6)
-				return player
+				g.Players[1].Hand = testCards(card.Rank6)
 			},
-			input:       "6",
-			expectError: true,
+			cards:         testCards(card.Rank6),
+			expectedError: ErrCannotBeat,
 			assertState: func(t *testing.T, g *Game) {
 				assert.Equal(t, card.RankQ, g.LastPlayedHand.KeyRank)
 				assert.Len(t, g.Players[1].Hand, 1)
 			},
 		},
 		{
-			name: "invalid play (cards not in hand)",
-			setupGame: func(g *Game) *Player {
-				return g.Players[0] // Hand is [3,4,5,K,K]
-			},
-			input:       "AA",
-			expectError: true,
+			name:          "invalid play (cards not in hand)",
+			setupGame:     func(g *Game) {}, // Hand is [3,4,5,K,K]
+			cards:         testCards(card.RankA, card.RankA),
+			expectedError: ErrCardsNotHeld,
 			assertState: func(t *testing.T, g *Game) {
 				assert.True(t, g.LastPlayedHand.IsEmpty())
 			},
@@ -222,12 +219,12 @@ func TestHandlePlay(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			g := setupTestGame()
-			player := tc.setupGame(g)
+			tc.setupGame(g)
 
-			err := g.handlePlay(player, tc.input)
+			err := g.Play(tc.cards)
 
-			if tc.expectError {
-				assert.Error(t, err)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -237,15 +234,11 @@ func TestHandlePlay(t *testing.T) {
 }
 
 // TestAdvanceToNextTurn tests the logic for advancing the turn and setting the next player's state.
+// It uses the real rule.CanBeatWithHand instead of swapping the package variable, so parallel tests cannot see a mock.
 func TestAdvanceToNextTurn(t *testing.T) {
-	// Mock the dependency on rule.CanBeatWithHand for predictable testing
-	originalCanBeatWithHand := rule.CanBeatWithHand
-	defer func() { rule.CanBeatWithHand = originalCanBeatWithHand }() // Restore after test
-
 	testCases := []struct {
 		name            string
 		setupGame       func(g *Game)
-		mockCanBeat     bool
 		expectedTurn    int
 		expectedCanPlay bool
 	}{
@@ -256,7 +249,6 @@ func TestAdvanceToNextTurn(t *testing.T) {
 				g.LastPlayerIdx = 0
 				g.LastPlayedHand = rule.ParsedHand{}
 			},
-			mockCanBeat:     true, // Irrelevant for this case
 			expectedTurn:    1,
 			expectedCanPlay: true,
 		},
@@ -265,9 +257,8 @@ func TestAdvanceToNextTurn(t *testing.T) {
 			setupGame: func(g *Game) {
 				g.CurrentTurn = 0
 				g.LastPlayerIdx = 0
-				g.LastPlayedHand, _ = rule.ParseHand(testCards(card.Rank3))
+				g.LastPlayedHand = mustParseHand(testCards(card.Rank3))
 			},
-			mockCanBeat:     true,
 			expectedTurn:    1,
 			expectedCanPlay: true,
 		},
@@ -276,9 +267,8 @@ func TestAdvanceToNextTurn(t *testing.T) {
 			setupGame: func(g *Game) {
 				g.CurrentTurn = 1
 				g.LastPlayerIdx = 1
-				g.LastPlayedHand, _ = rule.ParseHand(testCards(card.RankA))
+				g.LastPlayedHand = mustParseHand(testCards(card.Rank2)) // player 2's best single is also a 2
 			},
-			mockCanBeat:     false,
 			expectedTurn:    2,
 			expectedCanPlay: false,
 		},
@@ -290,11 +280,6 @@ func TestAdvanceToNextTurn(t *testing.T) {
 			g := setupTestGame()
 			tc.setupGame(g)
 
-			// Apply the mock for this specific test case
-			rule.CanBeatWithHand = func(playerHand []card.Card, opponentHand rule.ParsedHand) bool {
-				return tc.mockCanBeat
-			}
-
 			g.advanceToNextTurn()
 
 			assert.Equal(t, tc.expectedTurn, g.CurrentTurn)
@@ -303,8 +288,8 @@ func TestAdvanceToNextTurn(t *testing.T) {
 	}
 }
 
-// TestPlayTurn_Integration provides a simple integration test for a full turn.
-func TestPlayTurn_Integration(t *testing.T) {
+// TestPlay_Integration provides a simple integration test for a full turn.
+func TestPlay_Integration(t *testing.T) {
 	// A more linear test is better here than a complex table.
 	g := setupTestGame()
 
@@ -315,14 +300,14 @@ func TestPlayTurn_Integration(t *testing.T) {
 	g.CurrentTurn = 0
 
 	// Player 0 plays 3
-	err := g.PlayTurn("3")
+	err := g.Play(testCards(card.Rank3))
 	require.NoError(t, err)
 	assert.Equal(t, 1, g.CurrentTurn, "Turn should advance to Player 1")
 	assert.Equal(t, card.Rank3, g.LastPlayedHand.KeyRank)
 	assert.Len(t, g.Players[0].Hand, 1)
 
 	// Player 1 plays 4 (beating 3)
-	err = g.PlayTurn("4")
+	err = g.Play(testCards(card.Rank4))
 	require.NoError(t, err)
 	assert.Equal(t, 2, g.CurrentTurn, "Turn should advance to Player 2")
 	assert.Equal(t, card.Rank4, g.LastPlayedHand.KeyRank)
@@ -333,3 +318,103 @@ func TestPlayTurn_Integration(t *testing.T) {
 	require.True(t, isOver)
 	assert.Equal(t, g.Players[1].Name, winner.Name)
 }
+
+// TestNewGameWithSeed checks that a seed reproduces the deal, the first bidder and any redeal.
+func TestNewGameWithSeed(t *testing.T) {
+	t.Parallel()
+	start := func(seed int64) *Game {
+		g := NewGameWithSeed(seed)
+		g.Deal()
+		g.Bidding()
+		return g
+	}
+
+	g1, g2 := start(42), start(42)
+	assert.Equal(t, int64(42), g1.Seed)
+	assert.Equal(t, g1.Auction.First, g2.Auction.First)
+	assert.Equal(t, g1.LandlordCards, g2.LandlordCards)
+	for i := range g1.Players {
+		assert.Equal(t, g1.Players[i].Hand, g2.Players[i].Hand)
+	}
+
+	for range 3 {
+		require.NoError(t, g1.Bid(BidPass))
+		require.NoError(t, g2.Bid(BidPass))
+	}
+	assert.Equal(t, g1.Players[0].Hand, g2.Players[0].Hand, "redeals follow the seed too")
+
+	assert.NotEqual(t, g1.Players[0].Hand, start(43).Players[0].Hand)
+}
+
+// TestNewGameWithRules plays games with other seat counts and decks and rebuilds them from their records.
+func TestNewGameWithRules(t *testing.T) {
+	testCases := []struct {
+		name   string
+		rules  rule.RuleSet
+		hand   int // 每人发到的张数
+		bottom int
+		dead   int
+	}{
+		{name: "four players with two decks", rules: rule.FourPlayer, hand: 25, bottom: 8},
+		{name: "two players without 3s and 4s", rules: rule.TwoPlayer, hand: 17, bottom: 3, dead: 9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGameWithRules(5, tc.rules)
+			seats := tc.rules.PlayerCount()
+			require.Len(t, g.Players, seats)
+			g.Deal()
+			for _, p := range g.Players {
+				assert.Len(t, p.Hand, tc.hand)
+				assert.False(t, slices.ContainsFunc(p.Hand, func(c card.Card) bool { return c.Rank < tc.rules.LowestRank }))
+			}
+			assert.Len(t, g.LandlordCards, tc.bottom)
+			assert.Len(t, g.DeadCards, tc.dead)
+
+			g.Bidding()
+			for range seats {
+				require.NoError(t, g.Bid(BidPass))
+			}
+			require.NoError(t, g.Bid(BidThree))
+			landlord := g.Players[g.CurrentTurn]
+			assert.True(t, landlord.IsLandlord)
+			assert.Len(t, landlord.Hand, tc.hand+tc.bottom)
+
+			playOut(t, g, -1)
+			_, isOver := g.CheckWinner()
+			require.True(t, isOver)
+			remaining, held := 0, len(g.DeadCards)
+			for _, n := range g.CardCounter.GetRemainingCards() {
+				remaining += n
+			}
+			for _, p := range g.Players {
+				held += len(p.Hand)
+			}
+			assert.Equal(t, held, remaining, "the counter only knows the cards in play")
+
+			rebuilt, err := FromRecord(g.Record())
+			require.NoError(t, err)
+			assert.Equal(t, g.Rules, rebuilt.Rules)
+			assert.Equal(t, g.DeadCards, rebuilt.DeadCards)
+			assert.Equal(t, g.Record(), rebuilt.Record())
+		})
+	}
+}
+
+// TestHandlePass_TwoPlayers checks that a single pass ends the trick when there are only two seats.
+func TestHandlePass_TwoPlayers(t *testing.T) {
+	t.Parallel()
+	g := NewGameWithRules(1, rule.TwoPlayer)
+	g.Players[0].Hand = testCards(card.Rank6, card.Rank9)
+	g.Players[1].Hand = testCards(card.Rank5, card.RankK)
+	g.Phase = PhasePlaying
+
+	require.NoError(t, g.Play(testCards(card.Rank6)))
+	assert.Equal(t, 1, g.CurrentTurn)
+	require.NoError(t, g.Pass())
+	assert.True(t, g.LastPlayedHand.IsEmpty(), "one pass resets the trick")
+	assert.Equal(t, 0, g.CurrentTurn)
+	assert.ErrorIs(t, g.Pass(), ErrMustPlay)
+}
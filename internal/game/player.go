@@ -10,12 +10,16 @@ import (
 type Player struct {
 	Name       string
 	Hand       []card.Card
+	Played     []card.Card // 已经打出的牌
 	IsLandlord bool
 }
 
-// SortHand 从大到小排序
+// SortHand 从大到小排序，点数相同时按花色排序，保证同样的牌总是排成同样的顺序
 func (p *Player) SortHand() {
 	sort.Slice(p.Hand, func(i, j int) bool {
-		return p.Hand[i].Rank > p.Hand[j].Rank
+		if p.Hand[i].Rank != p.Hand[j].Rank {
+			return p.Hand[i].Rank > p.Hand[j].Rank
+		}
+		return p.Hand[i].Suit < p.Hand[j].Suit
 	})
 }
@@ -0,0 +1,208 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/record"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Move 记录一次出牌，Cards 为空表示 PASS
+type Move struct {
+	Seat  int
+	Cards []card.Card
+	Hand  rule.ParsedHand // 出牌的牌型，PASS 时为空
+}
+
+// Record 根据到目前为止的对局生成棋谱
+func (g *Game) Record() *record.Record {
+	rec := record.New()
+	rec.Seed = g.Seed
+	rec.Rules = g.Rules.String() + " " + g.BidStyle.String()
+	for _, p := range g.Players {
+		rec.Players = append(rec.Players, p.Name)
+	}
+	for _, bid := range g.Bids {
+		rec.Bids = append(rec.Bids, record.Bid{Seat: bid.Seat, Action: bid.Action.Notation()})
+	}
+	for i, p := range g.Players {
+		if p.IsLandlord {
+			rec.Landlord = i
+		}
+	}
+	for _, m := range g.Moves {
+		rec.Moves = append(rec.Moves, g.recordMove(m))
+	}
+	if winner, isOver := g.CheckWinner(); isOver {
+		rec.Result = record.ResultFarmers
+		if winner.IsLandlord {
+			rec.Result = record.ResultLandlord
+		}
+	}
+	return rec
+}
+
+// FromRecord 按棋谱的种子重新发牌，再依次重放叫地主和出牌，得到棋谱结束时的对局
+// 花色不影响对局，出牌时按点数从手牌中取牌。
+func FromRecord(rec *record.Record) (*Game, error) {
+	rules, style, err := parseRules(rec.Rules)
+	if err != nil {
+		return nil, err
+	}
+	g := NewGameWithRules(rec.Seed, rules)
+	g.BidStyle = style
+	for i, name := range rec.Players {
+		if i < len(g.Players) && name != "" {
+			g.Players[i].Name = name
+		}
+	}
+
+	g.Deal()
+	first := g.firstBidder
+	if len(rec.Bids) > 0 {
+		first = rec.Bids[0].Seat
+	}
+	if first < 0 || first >= len(g.Players) {
+		return nil, fmt.Errorf("座位 %d 不存在", first+1)
+	}
+	g.StartBidding(first)
+	for i, bid := range rec.Bids {
+		if bid.Seat != g.CurrentTurn || g.Phase != PhaseBidding {
+			return nil, fmt.Errorf("第 %d 次叫地主: 现在不是座位 %d 叫地主", i+1, bid.Seat+1)
+		}
+		action, err := ParseBidNotation(bid.Action)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 次叫地主: %w", i+1, err)
+		}
+		if err := g.Bid(action); err != nil {
+			return nil, fmt.Errorf("第 %d 次叫地主: %w", i+1, err)
+		}
+	}
+
+	for i, m := range rec.Moves {
+		if m.Seat != g.CurrentTurn {
+			return nil, fmt.Errorf("第 %d 手: 现在不是座位 %d 出牌", i+1, m.Seat+1)
+		}
+		var err error
+		if len(m.Ranks) == 0 {
+			err = g.Pass()
+		} else if cards := g.cardsOfMove(g.Players[m.Seat].Hand, m); len(cards) != len(m.Ranks) {
+			err = errors.New("出牌无效: 手牌中没有这些牌")
+		} else {
+			err = g.replayPlay(cards, m)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 手: %w", i+1, err)
+		}
+	}
+	return g, nil
+}
+
+// recordMove 把一手出牌写成棋谱记录，不是默认牌型的出牌把主体写在带牌前面
+// 癞子记为它打出时的点数。
+func (g *Game) recordMove(m Move) record.Move {
+	move := record.Move{Seat: m.Seat}
+	cards := m.Cards
+	if hands, err := rule.ParseHand(g.Rules, m.Cards); err == nil && !hands[0].SameAs(m.Hand) {
+		body := m.Hand.Body()
+		cards = bodyFirst(cards, body)
+		move.Body = len(body)
+	}
+	for _, c := range cards {
+		move.Ranks = append(move.Ranks, c.EffectiveRank())
+		move.Wild = append(move.Wild, c.Substituted())
+	}
+	if !slices.Contains(move.Wild, true) {
+		move.Wild = nil
+	}
+	return move
+}
+
+// replayPlay 重放棋谱中的一手出牌，有多种牌型时按棋谱中的主体选择，没有写明时按默认的解释
+func (g *Game) replayPlay(cards []card.Card, m record.Move) error {
+	options, err := g.Interpretations(cards)
+	if err != nil {
+		return err
+	}
+	if m.Body == 0 {
+		return g.PlayHand(options[0])
+	}
+	body := slices.Sorted(slices.Values(m.Ranks[:m.Body]))
+	for _, h := range options {
+		if slices.Equal(slices.Sorted(slices.Values(h.Body())), body) {
+			return g.PlayHand(h)
+		}
+	}
+	return fmt.Errorf("%w: 没有以 %s 为主体的牌型", ErrInvalidHand, record.FormatRanks(m.Ranks[:m.Body]))
+}
+
+// bodyFirst 把打出时点数属于 body 的牌排在前面，每个点数只取一次
+func bodyFirst(cards []card.Card, body []card.Rank) []card.Card {
+	left := slices.Clone(cards)
+	sorted := make([]card.Card, 0, len(cards))
+	for _, r := range body {
+		if i := slices.IndexFunc(left, func(c card.Card) bool { return c.EffectiveRank() == r }); i >= 0 {
+			sorted = append(sorted, left[i])
+			left = slices.Delete(left, i, i+1)
+		}
+	}
+	return append(sorted, left...)
+}
+
+// parseRules 读取棋谱的 Rules 头部，它由牌型规则和叫地主方式组成，例如 "classic points"
+// 只有叫地主方式的旧棋谱使用经典规则。
+func parseRules(s string) (rule.RuleSet, BidStyle, error) {
+	rules, style := rule.Classic, BidStylePoints
+	for _, field := range strings.Fields(s) {
+		if bs, err := ParseBidStyle(field); err == nil {
+			style = bs
+			continue
+		}
+		rs, err := rule.ParseRuleSet(field)
+		if err != nil {
+			return rule.RuleSet{}, 0, err
+		}
+		rules = rs
+	}
+	return rules, style, nil
+}
+
+// cardsOfMove 按棋谱的一手出牌从手牌中取牌，癞子都记下它打出时的点数
+// 手牌不够时返回的牌会少于 m.Ranks。
+func (g *Game) cardsOfMove(hand []card.Card, m record.Move) []card.Card {
+	ranks := slices.Clone(m.Ranks)
+	for i, wild := range m.Wild {
+		if wild {
+			ranks[i] = g.Rules.Wild
+		}
+	}
+	cards := cardsOfRanks(hand, ranks)
+	if len(cards) == len(m.Ranks) {
+		for i := range cards {
+			if g.Rules.IsWild(cards[i]) {
+				cards[i].As = m.Ranks[i]
+			}
+		}
+	}
+	return cards
+}
+
+// cardsOfRanks 按点数从手牌中取牌，手牌不够时返回的牌会少于 ranks
+func cardsOfRanks(hand []card.Card, ranks []card.Rank) []card.Card {
+	used := make([]bool, len(hand))
+	var cards []card.Card
+	for _, r := range ranks {
+		for i, c := range hand {
+			if !used[i] && c.Rank == r {
+				used[i] = true
+				cards = append(cards, c)
+				break
+			}
+		}
+	}
+	return cards
+}
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/record"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func handRanks(hand []card.Card) []card.Rank {
+	ranks := make([]card.Rank, len(hand))
+	for i, c := range hand {
+		ranks[i] = c.Rank
+	}
+	return ranks
+}
+
+// playOut finishes a game with timeout moves, which always lead the smallest card and pass otherwise.
+func playOut(t *testing.T, g *Game, moves int) {
+	t.Helper()
+	for i := 0; i < moves || moves < 0; i++ {
+		if _, isOver := g.CheckWinner(); isOver {
+			return
+		}
+		if cards := g.TimeoutMove(); len(cards) > 0 {
+			require.NoError(t, g.Play(cards))
+		} else {
+			require.NoError(t, g.Pass())
+		}
+	}
+}
+
+// TestGame_Record checks that a game can be written out and rebuilt from its record.
+func TestGame_Record(t *testing.T) {
+	testCases := []struct {
+		name  string
+		moves int // -1 表示打完整局
+		rules rule.RuleSet
+	}{
+		{name: "finished game", moves: -1, rules: rule.Classic},
+		{name: "game in progress", moves: 10, rules: rule.Classic},
+		{name: "mobile rules", moves: -1, rules: rule.Mobile},
+		{name: "laizi rules", moves: -1, rules: rule.Laizi},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGameWithSeed(7)
+			g.Rules = tc.rules
+			g.Deal()
+			g.Bidding()
+			for range 3 {
+				require.NoError(t, g.Bid(BidPass)) // 重新发牌也要能重放
+			}
+			require.NoError(t, g.Bid(BidTwo))
+			require.NoError(t, g.Bid(BidPass))
+			require.NoError(t, g.Bid(BidPass))
+			playOut(t, g, tc.moves)
+
+			rec, err := record.ParseString(g.Record().String())
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), rec.Seed)
+			assert.Len(t, rec.Bids, 6)
+
+			rebuilt, err := FromRecord(rec)
+			require.NoError(t, err)
+			assert.Equal(t, g.Rules, rebuilt.Rules)
+			assert.Equal(t, tc.rules.Name, rebuilt.Rules.Name)
+			assert.Equal(t, g.CurrentTurn, rebuilt.CurrentTurn)
+			assert.Equal(t, g.LastPlayerIdx, rebuilt.LastPlayerIdx)
+			assert.Equal(t, g.BaseScore, rebuilt.BaseScore)
+			assert.Equal(t, handRanks(g.LandlordCards), handRanks(rebuilt.LandlordCards))
+			for i := range g.Players {
+				assert.Equal(t, g.Players[i].IsLandlord, rebuilt.Players[i].IsLandlord)
+				assert.Equal(t, handRanks(g.Players[i].Hand), handRanks(rebuilt.Players[i].Hand))
+			}
+			assert.Equal(t, g.Record(), rebuilt.Record())
+		})
+	}
+}
+
+// TestGame_RecordWildcards checks that the ranks wildcards stand in for survive a record round trip.
+func TestGame_RecordWildcards(t *testing.T) {
+	t.Parallel()
+	g := NewGameWithSeed(7)
+	g.Rules = rule.Laizi
+	g.Deal()
+	g.Bidding()
+	require.NoError(t, g.Bid(BidThree))
+	require.NotZero(t, g.Rules.Wild)
+
+	// 找一手用癞子代替了别的点数的出牌
+	plays := rule.EnumerateLegalPlays(g.Rules, g.Players[g.CurrentTurn].Hand, rule.ParsedHand{})
+	i := slices.IndexFunc(plays, func(h rule.ParsedHand) bool {
+		return slices.ContainsFunc(h.Cards, card.Card.Substituted)
+	})
+	require.GreaterOrEqual(t, i, 0)
+	require.NoError(t, g.PlayHand(plays[i]))
+	assert.Contains(t, g.Record().String(), "*")
+
+	rec, err := record.ParseString(g.Record().String())
+	require.NoError(t, err)
+	rebuilt, err := FromRecord(rec)
+	require.NoError(t, err)
+	assert.True(t, plays[i].SameAs(rebuilt.LastPlayedHand))
+	assert.Equal(t, g.Record(), rebuilt.Record())
+	for i := range g.Players {
+		assert.Equal(t, handRanks(g.Players[i].Hand), handRanks(rebuilt.Players[i].Hand))
+	}
+}
+
+// TestFromRecord_Errors checks that records which do not fit the deal are rejected.
+func TestFromRecord_Errors(t *testing.T) {
+	g := NewGameWithSeed(7)
+	g.Deal()
+	g.Bidding()
+	require.NoError(t, g.Bid(BidThree))
+	first := g.CurrentTurn
+
+	rec := g.Record()
+	rec.Moves = []record.Move{{Seat: (first + 1) % 3, Ranks: []card.Rank{card.Rank3}}}
+	_, err := FromRecord(rec)
+	assert.Error(t, err, "wrong seat")
+
+	rec.Moves = []record.Move{{Seat: first}}
+	_, err = FromRecord(rec)
+	assert.Error(t, err, "cannot pass when leading")
+
+	rec.Moves = []record.Move{{Seat: first, Ranks: []card.Rank{card.RankRedJoker, card.RankRedJoker}}}
+	_, err = FromRecord(rec)
+	assert.Error(t, err, "cards not in hand")
+
+	rec = g.Record()
+	rec.Rules = "unknown"
+	_, err = FromRecord(rec)
+	assert.Error(t, err)
+
+	// 旧的棋谱只记录叫分方式，按经典规则重放
+	rec = g.Record()
+	rec.Rules = "points"
+	rebuilt, err := FromRecord(rec)
+	require.NoError(t, err)
+	assert.Equal(t, rule.Classic, rebuilt.Rules)
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// SaveVersion 存档格式的版本，格式不兼容地改变时加一
+const SaveVersion = 1
+
+// saveFile 存档的 JSON 格式
+type saveFile struct {
+	Version              int               `json:"version"`
+	Seed                 int64             `json:"seed"`
+	Redeals              int               `json:"redeals"` // 重新发牌的次数，读档时据此恢复随机数状态
+	BidStyle             string            `json:"bid_style"`
+	Rules                rule.RuleSet      `json:"rules"`
+	Phase                Phase             `json:"phase"`
+	Players              []savedPlayer     `json:"players"`
+	Deck                 card.Deck         `json:"deck"`
+	LandlordCards        []card.Card       `json:"landlord_cards"`
+	DeadCards            []card.Card       `json:"dead_cards,omitempty"`
+	Auction              *savedAuction     `json:"auction,omitempty"`
+	BaseScore            int               `json:"base_score"`
+	CurrentTurn          int               `json:"current_turn"`
+	LastPlayedHand       rule.ParsedHand   `json:"last_played_hand"`
+	LastPlayerIdx        int               `json:"last_player"`
+	ConsecutivePasses    int               `json:"consecutive_passes"`
+	CardCounter          *card.CardCounter `json:"card_counter"`
+	CanCurrentPlayerPlay bool              `json:"can_current_player_play"`
+	Bids                 []Bid             `json:"bids"`
+	Moves                []Move            `json:"moves"`
+	Practice             bool              `json:"practice"`
+}
+
+type savedPlayer struct {
+	Name       string      `json:"name"`
+	Hand       []card.Card `json:"hand"`
+	Played     []card.Card `json:"played"`
+	IsLandlord bool        `json:"landlord"`
+}
+
+// savedAuction 只保存叫地主的起点和历史，读档时重放历史恢复状态机
+type savedAuction struct {
+	First   int   `json:"first"`
+	History []Bid `json:"history"`
+}
+
+// Save 把整局游戏的状态写成 JSON 存档
+func (g *Game) Save(w io.Writer) error {
+	f := saveFile{
+		Version:              SaveVersion,
+		Seed:                 g.Seed,
+		Redeals:              g.redeals,
+		BidStyle:             g.BidStyle.String(),
+		Rules:                g.Rules,
+		Phase:                g.Phase,
+		Deck:                 g.Deck,
+		LandlordCards:        g.LandlordCards,
+		DeadCards:            g.DeadCards,
+		BaseScore:            g.BaseScore,
+		CurrentTurn:          g.CurrentTurn,
+		LastPlayedHand:       g.LastPlayedHand,
+		LastPlayerIdx:        g.LastPlayerIdx,
+		ConsecutivePasses:    g.ConsecutivePasses,
+		CardCounter:          g.CardCounter,
+		CanCurrentPlayerPlay: g.CanCurrentPlayerPlay,
+		Bids:                 g.Bids,
+		Moves:                g.Moves,
+		Practice:             g.Practice,
+	}
+	for _, p := range g.Players {
+		f.Players = append(f.Players, savedPlayer{Name: p.Name, Hand: p.Hand, Played: p.Played, IsLandlord: p.IsLandlord})
+	}
+	if g.Auction != nil {
+		f.Auction = &savedAuction{First: g.Auction.First, History: g.Auction.History}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// Load 读取 Save 写出的存档，恢复到保存时的局面
+func Load(r io.Reader) (*Game, error) {
+	var f saveFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("读取存档失败: %w", err)
+	}
+	if f.Version != SaveVersion {
+		return nil, fmt.Errorf("不支持的存档版本 %d", f.Version)
+	}
+
+	g := NewGameWithRules(f.Seed, f.Rules)
+	if len(f.Players) != len(g.Players) {
+		return nil, fmt.Errorf("存档中有 %d 名玩家，需要 %d 名", len(f.Players), len(g.Players))
+	}
+	if f.CurrentTurn < 0 || f.CurrentTurn >= len(g.Players) || f.LastPlayerIdx < 0 || f.LastPlayerIdx >= len(g.Players) {
+		return nil, errors.New("存档中的座位不存在")
+	}
+	if f.CardCounter == nil {
+		return nil, errors.New("存档中没有记牌器")
+	}
+	style, err := ParseBidStyle(f.BidStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	// 重新发牌会继续使用同一个随机数生成器，按次数重放洗牌和翻癞子使之后的发牌和原来的一致
+	if f.Rules.WildCards {
+		drawWild(g.rng)
+	}
+	for range f.Redeals {
+		f.Rules.NewDeck().Shuffle(g.rng)
+		if f.Rules.WildCards {
+			drawWild(g.rng)
+		}
+	}
+	g.redeals = f.Redeals
+
+	for i, p := range f.Players {
+		g.Players[i] = &Player{Name: p.Name, Hand: p.Hand, Played: p.Played, IsLandlord: p.IsLandlord}
+	}
+	g.BidStyle = style
+	g.Rules = f.Rules
+	g.Phase = f.Phase
+	g.Deck = f.Deck
+	g.LandlordCards = f.LandlordCards
+	g.DeadCards = f.DeadCards
+	g.BaseScore = f.BaseScore
+	g.CurrentTurn = f.CurrentTurn
+	g.LastPlayedHand = f.LastPlayedHand
+	g.LastPlayerIdx = f.LastPlayerIdx
+	g.ConsecutivePasses = f.ConsecutivePasses
+	g.CardCounter = f.CardCounter
+	g.CanCurrentPlayerPlay = f.CanCurrentPlayerPlay
+	g.Bids = f.Bids
+	g.Moves = f.Moves
+	for i, m := range g.Moves {
+		// 没有记录牌型的存档按默认的解释
+		if len(m.Cards) > 0 && m.Hand.IsEmpty() {
+			hands, err := rule.ParseHand(g.Rules, m.Cards)
+			if err != nil {
+				return nil, fmt.Errorf("存档中第 %d 手出牌无效: %w", i+1, err)
+			}
+			g.Moves[i].Hand = hands[0]
+		}
+		g.Moves[i].Hand = bombLength(g.Moves[i].Hand)
+	}
+	g.LastPlayedHand = bombLength(g.LastPlayedHand)
+	g.Practice = f.Practice
+
+	if f.Auction != nil {
+		if f.Auction.First < 0 || f.Auction.First >= len(g.Players) {
+			return nil, errors.New("存档中的座位不存在")
+		}
+		g.Auction = NewAuction(style, f.Auction.First, len(g.Players))
+		for _, bid := range f.Auction.History {
+			if err := g.Auction.Place(bid.Action); err != nil {
+				return nil, fmt.Errorf("恢复叫地主状态失败: %w", err)
+			}
+		}
+	}
+	return g, nil
+}
+
+// bombLength 旧存档中炸弹的 Length 为 0，补上炸弹的张数
+func bombLength(h rule.ParsedHand) rule.ParsedHand {
+	if h.Type.IsBomb() && h.Type != rule.Rocket && h.Length == 0 {
+		h.Length = len(h.Cards)
+	}
+	return h
+}
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveAndLoad round-trips a game through a save file.
+func saveAndLoad(t *testing.T, g *Game) *Game {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, g.Save(&buf))
+	loaded, err := Load(&buf)
+	require.NoError(t, err)
+	return loaded
+}
+
+// TestGame_SaveLoad checks that a loaded game has the same state and plays on exactly like the original.
+func TestGame_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		rules rule.RuleSet // 为空时使用经典规则
+		setup func(t *testing.T, g *Game)
+	}{
+		{
+			name: "during bidding",
+			setup: func(t *testing.T, g *Game) {
+				require.NoError(t, g.Bid(BidOne))
+			},
+		},
+		{
+			name: "after a redeal",
+			setup: func(t *testing.T, g *Game) {
+				for range 3 {
+					require.NoError(t, g.Bid(BidPass))
+				}
+			},
+		},
+		{
+			name:  "laizi after a redeal",
+			rules: rule.Laizi,
+			setup: func(t *testing.T, g *Game) {
+				for range 3 {
+					require.NoError(t, g.Bid(BidPass))
+				}
+			},
+		},
+		{
+			name:  "four players after a redeal",
+			rules: rule.FourPlayer,
+			setup: func(t *testing.T, g *Game) {
+				for range 4 {
+					require.NoError(t, g.Bid(BidPass))
+				}
+			},
+		},
+		{
+			name:  "two players after a redeal",
+			rules: rule.TwoPlayer,
+			setup: func(t *testing.T, g *Game) {
+				for range 2 {
+					require.NoError(t, g.Bid(BidPass))
+				}
+			},
+		},
+		{
+			name: "during play",
+			setup: func(t *testing.T, g *Game) {
+				require.NoError(t, g.Bid(BidThree))
+				playOut(t, g, 7)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rules := rule.Classic
+			if tc.rules.Name != "" {
+				rules = tc.rules
+			}
+			g := NewGameWithRules(42, rules)
+			g.Deal()
+			g.Bidding()
+			tc.setup(t, g)
+
+			loaded := saveAndLoad(t, g)
+			for i, p := range g.Players {
+				assert.Equal(t, *p, *loaded.Players[i])
+			}
+			assert.Equal(t, g.LandlordCards, loaded.LandlordCards)
+			assert.Equal(t, g.DeadCards, loaded.DeadCards)
+			assert.Equal(t, g.CardCounter.GetRemainingCards(), loaded.CardCounter.GetRemainingCards())
+			assert.Equal(t, g.LastPlayedHand, loaded.LastPlayedHand)
+			assert.Equal(t, g.ConsecutivePasses, loaded.ConsecutivePasses)
+			assert.Equal(t, g.CurrentTurn, loaded.CurrentTurn)
+			assert.Equal(t, g.Seed, loaded.Seed)
+			assert.Equal(t, g.Rules, loaded.Rules)
+			assert.Equal(t, g.Auction, loaded.Auction)
+
+			// 两局继续下去（包括再次重新发牌）应该完全一样
+			for _, game := range []*Game{g, loaded} {
+				for game.Phase == PhaseBidding {
+					action := BidPass
+					if len(game.Bids) >= 9 {
+						actions := game.Auction.ValidActions()
+						action = actions[len(actions)-1]
+					}
+					require.NoError(t, game.Bid(action))
+				}
+				playOut(t, game, -1)
+			}
+			assert.Equal(t, g.Record().String(), loaded.Record().String())
+		})
+	}
+}
+
+// TestLoad_OldBombLength loads a save written before bombs recorded their size and redoes the bomb.
+func TestLoad_OldBombLength(t *testing.T) {
+	t.Parallel()
+
+	g := setupTestGame()
+	g.Practice = true
+	g.Players[0].Hand = testCards(card.Rank3, card.Rank9, card.Rank9, card.Rank9, card.Rank9)
+	require.NoError(t, g.Play(testCards(card.Rank9, card.Rank9, card.Rank9, card.Rank9)))
+	require.Equal(t, 4, g.Moves[0].Hand.Length)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.Save(&buf))
+	old := strings.ReplaceAll(buf.String(), `"Length": 4`, `"Length": 0`)
+	require.NotEqual(t, buf.String(), old)
+
+	loaded, err := Load(strings.NewReader(old))
+	require.NoError(t, err)
+	assert.Equal(t, 4, loaded.Moves[0].Hand.Length)
+	assert.Equal(t, 4, loaded.LastPlayedHand.Length)
+
+	require.NoError(t, loaded.Undo())
+	require.NoError(t, loaded.Redo())
+	assert.Equal(t, rule.Bomb, loaded.LastPlayedHand.Type)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	g := NewGameWithSeed(1)
+	g.Deal()
+	g.Bidding()
+	var buf bytes.Buffer
+	require.NoError(t, g.Save(&buf))
+	saved := buf.String()
+
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "not json", data: "ddz"},
+		{name: "unknown version", data: strings.Replace(saved, `"version": 1`, `"version": 99`, 1)},
+		{name: "bad seat", data: strings.Replace(saved, `"last_player": 0`, `"last_player": 5`, 1)},
+		{name: "unknown bid style", data: strings.Replace(saved, `"bid_style": "points"`, `"bid_style": "auction"`, 1)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Load(strings.NewReader(tc.data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Settlement 一局结束后的结算
+type Settlement struct {
+	LandlordWins bool
+	BaseScore    int   // 底分，由叫地主决定
+	Bombs        int   // 打出的炸弹数
+	Rockets      int   // 打出的王炸数
+	Spring       bool  // 春天：地主获胜且农民一张牌都没出
+	AntiSpring   bool  // 反春：农民获胜且地主只出了第一手牌
+	Multiplier   int   // 总倍数：每个炸弹、王炸、春天或反春翻一倍
+	Points       []int // 每个座位的得分，地主输赢的是每个农民的总和；两人玩法中唯一的农民输赢两份
+}
+
+// Multiplier 到目前为止的倍数，只计算已经打出的炸弹和王炸
+func (g *Game) Multiplier() int {
+	bombs, rockets := g.bombCount()
+	return 1 << (bombs + rockets)
+}
+
+// Settle 结算已经结束的一局
+func (g *Game) Settle() (Settlement, error) {
+	winner, isOver := g.CheckWinner()
+	if !isOver {
+		return Settlement{}, errors.New("游戏还没有结束")
+	}
+
+	s := Settlement{LandlordWins: winner.IsLandlord, BaseScore: g.BaseScore}
+	s.Bombs, s.Rockets = g.bombCount()
+
+	landlordHands, farmerHands := 0, 0
+	for _, m := range g.Moves {
+		if len(m.Cards) == 0 {
+			continue
+		}
+		if g.Players[m.Seat].IsLandlord {
+			landlordHands++
+		} else {
+			farmerHands++
+		}
+	}
+	s.Spring = s.LandlordWins && farmerHands == 0
+	s.AntiSpring = !s.LandlordWins && landlordHands == 1
+
+	doubles := s.Bombs + s.Rockets
+	if s.Spring || s.AntiSpring {
+		doubles++
+	}
+	s.Multiplier = 1 << doubles
+
+	// 每个农民和地主单独结算
+	points := s.BaseScore * s.Multiplier
+	if !s.LandlordWins {
+		points = -points
+	}
+	// 两人玩法中唯一的农民承担三人玩法中两个农民的输赢，地主的得失和三人时一样
+	if len(g.Players) == 2 {
+		points *= 2
+	}
+	s.Points = make([]int, len(g.Players))
+	for i, p := range g.Players {
+		if p.IsLandlord {
+			continue
+		}
+		s.Points[i] = -points
+		for j, q := range g.Players {
+			if q.IsLandlord {
+				s.Points[j] += points
+			}
+		}
+	}
+	return s, nil
+}
+
+// bombCount 统计已经打出的炸弹和王炸，软炸和纯癞子炸弹都算作炸弹
+func (g *Game) bombCount() (bombs, rockets int) {
+	for _, m := range g.Moves {
+		switch m.Hand.Type {
+		case rule.Bomb, rule.SoftBomb, rule.WildBomb:
+			bombs++
+		case rule.Rocket:
+			rockets++
+		}
+	}
+	return bombs, rockets
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// played 构造一手出牌记录，cards 为空表示 PASS
+func played(seat int, cards []card.Card) Move {
+	if len(cards) == 0 {
+		return Move{Seat: seat}
+	}
+	return Move{Seat: seat, Cards: cards, Hand: mustParseHand(cards)}
+}
+
+// TestGame_Settle uses a table to test multipliers, spring detection and point transfers.
+func TestGame_Settle(t *testing.T) {
+	bomb := testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5)
+	rocket := testCards(card.RankBlackJoker, card.RankRedJoker)
+
+	testCases := []struct {
+		name     string
+		winner   int
+		moves    []Move
+		expected Settlement
+	}{
+		{
+			name:   "landlord wins with a bomb",
+			winner: 0,
+			moves:  []Move{played(0, testCards(card.Rank3)), played(1, testCards(card.Rank4)), played(2, nil), played(0, bomb)},
+			expected: Settlement{
+				LandlordWins: true, BaseScore: 2, Bombs: 1, Multiplier: 2,
+				Points: []int{8, -4, -4},
+			},
+		},
+		{
+			name:   "spring doubles on top of the rocket",
+			winner: 0,
+			moves:  []Move{played(0, testCards(card.Rank3)), played(1, nil), played(2, nil), played(0, rocket)},
+			expected: Settlement{
+				LandlordWins: true, BaseScore: 2, Rockets: 1, Spring: true, Multiplier: 4,
+				Points: []int{16, -8, -8},
+			},
+		},
+		{
+			name:   "anti-spring when the landlord only played the first hand",
+			winner: 2,
+			moves:  []Move{played(0, testCards(card.Rank3)), played(1, testCards(card.Rank4)), played(2, bomb), played(0, nil), played(1, nil), played(2, testCards(card.Rank6))},
+			expected: Settlement{
+				BaseScore: 2, Bombs: 1, AntiSpring: true, Multiplier: 4,
+				Points: []int{-16, 8, 8},
+			},
+		},
+		{
+			name:   "farmers win without multipliers",
+			winner: 1,
+			moves:  []Move{played(0, testCards(card.Rank3)), played(1, testCards(card.Rank4)), played(2, nil), played(0, testCards(card.Rank9)), played(1, testCards(card.Rank2))},
+			expected: Settlement{
+				BaseScore: 2, Multiplier: 1,
+				Points: []int{-4, 2, 2},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := setupTestGame()
+			g.Players[0].IsLandlord = true
+			g.BaseScore = 2
+			g.Moves = tc.moves
+			g.Players[tc.winner].Hand = nil
+
+			s, err := g.Settle()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s)
+			assert.Zero(t, s.Points[0]+s.Points[1]+s.Points[2], "points are zero-sum")
+		})
+	}
+}
+
+// TestGame_SettleTwoPlayers checks that the single farmer of a heads-up game settles both farmer shares.
+func TestGame_SettleTwoPlayers(t *testing.T) {
+	bomb := testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5)
+
+	testCases := []struct {
+		name     string
+		winner   int
+		moves    []Move
+		expected []int
+	}{
+		{
+			name:     "landlord wins with a bomb",
+			winner:   1,
+			moves:    []Move{played(1, testCards(card.Rank5)), played(0, testCards(card.Rank6)), played(1, bomb)},
+			expected: []int{-12, 12},
+		},
+		{
+			name:     "farmer wins without multipliers",
+			winner:   0,
+			moves:    []Move{played(1, testCards(card.Rank5)), played(0, testCards(card.Rank6)), played(1, testCards(card.Rank7)), played(0, testCards(card.Rank8))},
+			expected: []int{6, -6},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGameWithRules(1, rule.TwoPlayer)
+			g.Players[0].Hand = testCards(card.Rank9)
+			g.Players[1].Hand = testCards(card.Rank9)
+			g.Players[1].IsLandlord = true
+			g.BaseScore = 3
+			g.Moves = tc.moves
+			g.Players[tc.winner].Hand = nil
+
+			s, err := g.Settle()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s.Points)
+			assert.Zero(t, s.Points[0]+s.Points[1], "points are zero-sum")
+		})
+	}
+}
+
+// TestGame_Multiplier checks the live multiplier and that unfinished games cannot be settled.
+func TestGame_Multiplier(t *testing.T) {
+	g := setupTestGame()
+	assert.Equal(t, 1, g.Multiplier())
+
+	g.Moves = []Move{played(0, testCards(card.Rank5, card.Rank5, card.Rank5, card.Rank5)), played(1, testCards(card.RankBlackJoker, card.RankRedJoker))}
+	assert.Equal(t, 4, g.Multiplier())
+
+	_, err := g.Settle()
+	assert.Error(t, err)
+}
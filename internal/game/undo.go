@@ -0,0 +1,102 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// 悔棋可能返回的错误
+var (
+	ErrUndoDisabled  = errors.New("只有练习模式可以悔棋")
+	ErrNothingToUndo = errors.New("没有可以悔的棋")
+	ErrNothingToRedo = errors.New("没有可以重做的棋")
+)
+
+// Undo 撤销出牌记录中的最后一手（出牌或 PASS），只在练习模式可用
+// 手牌、记牌器和出牌轮次都根据出牌记录恢复，撤销的一手可以用 Redo 重新走回来。
+func (g *Game) Undo() error {
+	if !g.Practice {
+		return ErrUndoDisabled
+	}
+	if g.Phase != PhasePlaying || len(g.Moves) == 0 {
+		return ErrNothingToUndo
+	}
+
+	last := g.Moves[len(g.Moves)-1]
+	g.Moves = g.Moves[:len(g.Moves)-1]
+	if len(last.Cards) > 0 {
+		p := g.Players[last.Seat]
+		for _, c := range last.Cards {
+			p.Hand = append(p.Hand, c.Natural()) // 收回的癞子不再代替别的点数
+		}
+		p.SortHand()
+		p.Played = p.Played[:len(p.Played)-len(last.Cards)]
+		g.CardCounter.Restore(last.Cards)
+	}
+	g.CurrentTurn = last.Seat
+	g.replayTrick()
+	g.updateCanPlay()
+	g.redo = append(g.redo, last)
+
+	g.publish(MoveUndone{Seat: last.Seat, Cards: last.Cards})
+	return nil
+}
+
+// Redo 重新走回最近一次 Undo 撤销的一手；Undo 之后如果走了别的牌，就不能再 Redo
+func (g *Game) Redo() error {
+	if !g.Practice {
+		return ErrUndoDisabled
+	}
+	if len(g.redo) == 0 {
+		return ErrNothingToRedo
+	}
+
+	next, rest := g.redo[len(g.redo)-1], g.redo[:len(g.redo)-1]
+	var err error
+	if len(next.Cards) == 0 {
+		err = g.Pass()
+	} else {
+		err = g.PlayHand(next.Hand)
+	}
+	if err != nil {
+		return err
+	}
+	g.redo = rest // Play 和 Pass 会清空 redo
+	return nil
+}
+
+// CanRedo 是否有可以 Redo 的一手
+func (g *Game) CanRedo() bool {
+	return g.Practice && len(g.redo) > 0
+}
+
+// replayTrick 按出牌记录重新计算这一轮的上家出牌和连续 PASS 的次数
+func (g *Game) replayTrick() {
+	g.LastPlayedHand = rule.ParsedHand{}
+	g.LastPlayerIdx = g.landlordSeat()
+	g.ConsecutivePasses = 0
+	for _, m := range g.Moves {
+		if len(m.Cards) > 0 {
+			g.LastPlayedHand = m.Hand
+			g.LastPlayerIdx = m.Seat
+			g.ConsecutivePasses = 0
+			continue
+		}
+		g.ConsecutivePasses++
+		if g.trickOver() {
+			g.LastPlayedHand = rule.ParsedHand{}
+			g.LastPlayerIdx = (m.Seat + 1) % len(g.Players)
+		}
+	}
+}
+
+// landlordSeat 地主的座位
+func (g *Game) landlordSeat() int {
+	for i, p := range g.Players {
+		if p.IsLandlord {
+			return i
+		}
+	}
+	return 0
+}
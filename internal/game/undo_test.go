@@ -0,0 +1,147 @@
+package game
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshot is the part of the game state that undo must restore.
+type snapshot struct {
+	Hands      [3][]card.Card
+	Played     [3]int
+	Remaining  map[card.Rank]int
+	LastHand   rule.ParsedHand
+	LastPlayer int
+	Passes     int
+	Turn       int
+	CanPlay    bool
+}
+
+func takeSnapshot(g *Game) snapshot {
+	s := snapshot{
+		Remaining:  maps.Clone(g.CardCounter.GetRemainingCards()),
+		LastHand:   g.LastPlayedHand,
+		LastPlayer: g.LastPlayerIdx,
+		Passes:     g.ConsecutivePasses,
+		Turn:       g.CurrentTurn,
+		CanPlay:    g.CanCurrentPlayerPlay,
+	}
+	for i, p := range g.Players {
+		s.Hands[i] = slices.Clone(p.Hand)
+		s.Played[i] = len(p.Played)
+	}
+	return s
+}
+
+// newPracticeGame deals a seeded practice game and finishes the bidding.
+func newPracticeGame(t *testing.T) *Game {
+	t.Helper()
+	g := NewGameWithSeed(9)
+	g.Practice = true
+	g.Deal()
+	g.Bidding()
+	require.NoError(t, g.Bid(BidThree))
+	return g
+}
+
+// TestGame_UndoRedo undoes every move of a full game back to the start and redoes it all.
+func TestGame_UndoRedo(t *testing.T) {
+	t.Parallel()
+
+	g := newPracticeGame(t)
+	var snapshots []snapshot
+	for {
+		snapshots = append(snapshots, takeSnapshot(g))
+		if _, isOver := g.CheckWinner(); isOver {
+			break
+		}
+		playOut(t, g, 1)
+	}
+	final := g.Record().String()
+
+	for i := len(snapshots) - 2; i >= 0; i-- {
+		require.NoError(t, g.Undo())
+		require.Equal(t, snapshots[i], takeSnapshot(g), "after undoing back to move %d", i)
+	}
+	assert.ErrorIs(t, g.Undo(), ErrNothingToUndo)
+	assert.Empty(t, g.Moves)
+
+	for i := 1; i < len(snapshots); i++ {
+		require.True(t, g.CanRedo())
+		require.NoError(t, g.Redo())
+		require.Equal(t, snapshots[i], takeSnapshot(g), "after redoing move %d", i)
+	}
+	assert.ErrorIs(t, g.Redo(), ErrNothingToRedo)
+	assert.Equal(t, final, g.Record().String())
+}
+
+// TestGame_UndoToSeatThatCannotBeat undoes a forced pass and checks the seat still cannot play.
+func TestGame_UndoToSeatThatCannotBeat(t *testing.T) {
+	t.Parallel()
+
+	g := setupTestGame()
+	g.Practice = true
+	g.Players[0].IsLandlord = true
+	require.NoError(t, g.Play(testCards(card.RankK)))
+	require.NoError(t, g.Play(testCards(card.RankA)))
+	require.NoError(t, g.Play(testCards(card.Rank2)))
+	require.False(t, g.CanCurrentPlayerPlay, "3 4 5 K K cannot beat a 2")
+	require.NoError(t, g.Pass())
+
+	require.NoError(t, g.Undo())
+	assert.Equal(t, 0, g.CurrentTurn)
+	assert.False(t, g.CanCurrentPlayerPlay)
+
+	require.NoError(t, g.Undo())
+	assert.Equal(t, 2, g.CurrentTurn)
+	assert.True(t, g.CanCurrentPlayerPlay, "2 2 beats the A")
+}
+
+func TestGame_UndoThenNewLine(t *testing.T) {
+	t.Parallel()
+
+	g := newPracticeGame(t)
+	playOut(t, g, 4)
+	require.NoError(t, g.Undo())
+	require.True(t, g.CanRedo())
+
+	// 悔棋之后走另一步，原来的走法不能再重做
+	hand := g.Players[g.CurrentTurn].Hand
+	if g.isFreePlay() {
+		require.NoError(t, g.Play(hand[:1]))
+	} else {
+		require.NoError(t, g.Pass())
+	}
+	assert.False(t, g.CanRedo())
+	assert.ErrorIs(t, g.Redo(), ErrNothingToRedo)
+}
+
+func TestGame_UndoDisabled(t *testing.T) {
+	t.Parallel()
+
+	g := newPracticeGame(t)
+	g.Practice = false
+	playOut(t, g, 2)
+	assert.ErrorIs(t, g.Undo(), ErrUndoDisabled)
+	assert.ErrorIs(t, g.Redo(), ErrUndoDisabled)
+	assert.Len(t, g.Moves, 2)
+}
+
+func TestGame_UndoEvent(t *testing.T) {
+	t.Parallel()
+
+	g := newPracticeGame(t)
+	playOut(t, g, 1)
+	played := g.Moves[0]
+
+	var events []Event
+	g.Subscribe(func(e Event) { events = append(events, e) })
+	require.NoError(t, g.Undo())
+	assert.Equal(t, []Event{MoveUndone{Seat: played.Seat, Cards: played.Cards}}, events)
+}
@@ -0,0 +1,257 @@
+package infer
+
+import (
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// noCap 表示这个点数没有上限
+const noCap = -1
+
+// Holding 对一个座位剩余手牌的推断，按点数给出张数的上下界
+type Holding struct {
+	Played []card.Card       // 已经打出的牌
+	Min    map[card.Rank]int // 每个点数至少还有几张，没有的点数不在表中
+	Max    map[card.Rank]int // 每个点数最多还有几张，没有的点数不在表中
+}
+
+// Possible 可能还有的点数，从小到大
+func (h Holding) Possible() []card.Rank {
+	return ranksOf(h.Max)
+}
+
+// Certain 一定还有的点数，从小到大
+func (h Holding) Certain() []card.Rank {
+	return ranksOf(h.Min)
+}
+
+func ranksOf(m map[card.Rank]int) []card.Rank {
+	var ranks []card.Rank
+	for r := card.Rank3; r <= card.RankRedJoker; r++ {
+		if m[r] > 0 {
+			ranks = append(ranks, r)
+		}
+	}
+	return ranks
+}
+
+// Inference 一个座位的视角下对每个座位手牌的推断
+type Inference struct {
+	Seat  int
+	Seats []Holding // 下标是座位，自己座位的上下界都等于手牌
+}
+
+// Infer 根据 view 中公开的信息推断每个座位的手牌
+// 依据有记牌器、每个座位剩余的张数、地主还没打出的底牌，以及对手的 PASS：
+// 假设一个人压得住对手的单张、对子或三张时不会放过，放过单张 K 就说明他没有比 K 大的牌。
+// 如果他之后打出了这样的牌，说明他是故意不要，他的 PASS 都不再作为依据。
+func Infer(view game.PlayerView) *Inference {
+	caps := passCaps(view)
+	minC, maxC, ok := bounds(view, caps)
+	if !ok {
+		// PASS 推出的上限和其他信息矛盾时只用确定的信息
+		minC, maxC, _ = bounds(view, nil)
+	}
+
+	in := &Inference{Seat: view.Seat, Seats: make([]Holding, len(view.HandSizes))}
+	for seat := range in.Seats {
+		h := Holding{Min: make(map[card.Rank]int), Max: make(map[card.Rank]int)}
+		if seat < len(view.Played) {
+			h.Played = view.Played[seat]
+		}
+		for r := card.Rank3; r <= card.RankRedJoker; r++ {
+			if minC[seat][r] > 0 {
+				h.Min[r] = minC[seat][r]
+			}
+			if maxC[seat][r] > 0 {
+				h.Max[r] = maxC[seat][r]
+			}
+		}
+		in.Seats[seat] = h
+	}
+	return in
+}
+
+// bounds 计算每个座位每个点数的上下界，caps 为 nil 时不使用 PASS 推出的上限
+// 上下界互相矛盾时 ok 为 false。
+func bounds(view game.PlayerView, caps []card.RankCounts) (minC, maxC []card.RankCounts, ok bool) {
+	n := len(view.HandSizes)
+	minC, maxC = make([]card.RankCounts, n), make([]card.RankCounts, n)
+
+	var unseen card.RankCounts
+	total := 0
+	for r, c := range view.Unseen {
+		unseen[r] = max(c, 0)
+		total += unseen[r]
+	}
+	// 不在任何对手手里的牌：两人玩法中不用的牌，以及叫地主阶段还没亮出的底牌
+	hidden := total
+	for seat, size := range view.HandSizes {
+		if seat != view.Seat {
+			hidden -= size
+		}
+	}
+	hidden = max(hidden, 0)
+
+	for _, c := range view.Hand {
+		minC[view.Seat][c.Rank]++
+		maxC[view.Seat][c.Rank]++
+	}
+	known := landlordBottom(view)
+	var others []int
+	for seat, size := range view.HandSizes {
+		if seat == view.Seat {
+			continue
+		}
+		others = append(others, seat)
+		for r := card.Rank3; r <= card.RankRedJoker; r++ {
+			maxC[seat][r] = min(unseen[r], size)
+			if caps != nil && caps[seat][r] != noCap {
+				maxC[seat][r] = min(maxC[seat][r], caps[seat][r])
+			}
+		}
+	}
+	if view.LandlordSeat >= 0 && view.LandlordSeat != view.Seat {
+		for r := card.Rank3; r <= card.RankRedJoker; r++ {
+			minC[view.LandlordSeat][r] = min(known[r], unseen[r])
+			if minC[view.LandlordSeat][r] > maxC[view.LandlordSeat][r] {
+				return minC, maxC, false
+			}
+		}
+	}
+
+	// 反复用各点数的总数和各座位的张数收紧上下界，直到不再变化
+	for changed := true; changed; {
+		changed = false
+		tighten := func(bound *int, value int, lower bool) {
+			if (lower && value > *bound) || (!lower && value < *bound) {
+				*bound, changed = value, true
+			}
+		}
+		for r := card.Rank3; r <= card.RankRedJoker; r++ {
+			sumMin, sumMax := 0, hidden
+			for _, seat := range others {
+				sumMin += minC[seat][r]
+				sumMax += maxC[seat][r]
+			}
+			for _, seat := range others {
+				tighten(&minC[seat][r], unseen[r]-(sumMax-maxC[seat][r]), true)
+				tighten(&maxC[seat][r], unseen[r]-(sumMin-minC[seat][r]), false)
+			}
+		}
+		for _, seat := range others {
+			sumMin, sumMax := 0, 0
+			for r := card.Rank3; r <= card.RankRedJoker; r++ {
+				sumMin += minC[seat][r]
+				sumMax += maxC[seat][r]
+			}
+			for r := card.Rank3; r <= card.RankRedJoker; r++ {
+				tighten(&minC[seat][r], view.HandSizes[seat]-(sumMax-maxC[seat][r]), true)
+				tighten(&maxC[seat][r], view.HandSizes[seat]-(sumMin-minC[seat][r]), false)
+			}
+		}
+		for _, seat := range others {
+			for r := card.Rank3; r <= card.RankRedJoker; r++ {
+				if minC[seat][r] > maxC[seat][r] {
+					return minC, maxC, false
+				}
+			}
+		}
+	}
+	return minC, maxC, true
+}
+
+// landlordBottom 地主还没打出的底牌，打出的牌先从底牌中扣除，得到的是一定还在他手里的下界
+func landlordBottom(view game.PlayerView) card.RankCounts {
+	var known card.RankCounts
+	if view.LandlordSeat < 0 {
+		return known
+	}
+	for _, c := range view.BottomCards {
+		known[c.Rank]++
+	}
+	if view.LandlordSeat < len(view.Played) {
+		for _, c := range view.Played[view.LandlordSeat] {
+			if known[c.Rank] > 0 {
+				known[c.Rank]--
+			}
+		}
+	}
+	return known
+}
+
+// passCaps 按出牌记录中对手的 PASS 推出每个座位现在每个点数最多有几张，noCap 表示没有上限
+func passCaps(view game.PlayerView) []card.RankCounts {
+	n := len(view.HandSizes)
+	caps := make([]card.RankCounts, n)
+	for seat := range caps {
+		for r := range caps[seat] {
+			caps[seat][r] = noCap
+		}
+	}
+	broken := make([]bool, n) // 打出过 PASS 时“没有”的牌，他的 PASS 不再作为依据
+
+	opponents := func(a, b int) bool {
+		return (a == view.LandlordSeat) != (b == view.LandlordSeat)
+	}
+	var last rule.ParsedHand
+	lastSeat, passes := -1, 0
+	for i, m := range view.History {
+		if len(m.Cards) > 0 {
+			last, lastSeat, passes = m.Hand, m.Seat, 0
+			continue
+		}
+		if w := passWidth(view.Rules, last); w > 0 && m.Seat != view.Seat && m.Seat < n && opponents(m.Seat, lastSeat) {
+			// PASS 时他比 last 大的点数都不到 w 张，之后每打出一张上限就少一张
+			var played card.RankCounts
+			for _, later := range view.History[i+1:] {
+				if later.Seat == m.Seat {
+					for _, c := range later.Cards {
+						played[c.Rank]++
+					}
+				}
+			}
+			for r := last.KeyRank + 1; r <= card.RankRedJoker; r++ {
+				limit := w - 1 - played[r]
+				if limit < 0 {
+					broken[m.Seat] = true
+				}
+				if caps[m.Seat][r] == noCap || limit < caps[m.Seat][r] {
+					caps[m.Seat][r] = limit
+				}
+			}
+		}
+		passes++
+		if passes == n-1 {
+			last = rule.ParsedHand{}
+		}
+	}
+
+	for seat := range caps {
+		if broken[seat] {
+			for r := range caps[seat] {
+				caps[seat][r] = noCap
+			}
+		}
+	}
+	return caps
+}
+
+// passWidth 放过 hand 能推出上限时返回它每个点数的张数，否则返回 0
+// 癞子玩法中任何一张牌配上癞子都能成对，只使用单张。
+func passWidth(rules rule.RuleSet, hand rule.ParsedHand) int {
+	switch hand.Type {
+	case rule.Single:
+		return 1
+	case rule.Pair:
+		if !rules.WildCards {
+			return 2
+		}
+	case rule.Trio:
+		if !rules.WildCards {
+			return 3
+		}
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+package infer
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/record"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cardsOf builds cards from record notation such as "55A2".
+func cardsOf(t *testing.T, s string) []card.Card {
+	t.Helper()
+	if s == "" {
+		return nil
+	}
+	ranks, err := record.ParseRanks(s)
+	require.NoError(t, err)
+	cards := make([]card.Card, len(ranks))
+	for i, r := range ranks {
+		cards[i] = card.Card{Rank: r}
+	}
+	return cards
+}
+
+// move builds a history entry, an empty s is a pass.
+func move(t *testing.T, seat int, s string) game.Move {
+	if s == "" {
+		return game.Move{Seat: seat}
+	}
+	cards := cardsOf(t, s)
+	hands, err := rule.ParseHand(rule.Classic, cards)
+	require.NoError(t, err)
+	return game.Move{Seat: seat, Cards: cards, Hand: hands[0]}
+}
+
+func TestInfer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		seat     int
+		landlord int
+		bottom   string
+		unseen   string // 自己看不到的牌，对手各有两张
+		history  func(t *testing.T) []game.Move
+		possible map[int]string // 每个对手可能有的点数
+		certain  map[int]string // 每个对手一定有的点数
+	}{
+		{
+			name:     "unplayed bottom cards stay with the landlord",
+			seat:     0,
+			landlord: 1,
+			bottom:   "2",
+			unseen:   "3AA2",
+			history:  func(t *testing.T) []game.Move { return nil },
+			possible: map[int]string{1: "3A2", 2: "3A"},
+			certain:  map[int]string{1: "2", 2: "A"},
+		},
+		{
+			name:     "a farmer who passed on a single K holds nothing above it",
+			seat:     0,
+			landlord: 0,
+			unseen:   "33A2",
+			history: func(t *testing.T) []game.Move {
+				return []game.Move{move(t, 0, "K"), move(t, 1, "")}
+			},
+			possible: map[int]string{1: "3", 2: "A2"},
+			certain:  map[int]string{1: "3", 2: "A2"},
+		},
+		{
+			name:     "passing on a teammate says nothing",
+			seat:     1,
+			landlord: 0,
+			unseen:   "3AA2",
+			history: func(t *testing.T) []game.Move {
+				return []game.Move{move(t, 1, "K"), move(t, 2, "")}
+			},
+			possible: map[int]string{0: "3A2", 2: "3A2"},
+			certain:  map[int]string{},
+		},
+		{
+			name:     "a later play shows the pass was a choice",
+			seat:     0,
+			landlord: 0,
+			unseen:   "3AA2",
+			history: func(t *testing.T) []game.Move {
+				return []game.Move{
+					move(t, 0, "K"), move(t, 1, ""), move(t, 2, "BR"), move(t, 0, ""), move(t, 1, ""),
+					move(t, 2, "4"), move(t, 0, ""), move(t, 1, "2"),
+				}
+			},
+			possible: map[int]string{1: "3A2", 2: "3A2"},
+			certain:  map[int]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			view := game.PlayerView{
+				Seat:         tc.seat,
+				Hand:         cardsOf(t, "9"),
+				Phase:        game.PhasePlaying,
+				LandlordSeat: tc.landlord,
+				HandSizes:    []int{2, 2, 2},
+				Unseen:       make(map[card.Rank]int),
+				Played:       make([][]card.Card, 3),
+				History:      tc.history(t),
+				BottomCards:  cardsOf(t, tc.bottom),
+				Rules:        rule.Classic,
+			}
+			view.HandSizes[tc.seat] = len(view.Hand)
+			for _, m := range view.History {
+				view.Played[m.Seat] = append(view.Played[m.Seat], m.Cards...)
+			}
+			for _, c := range cardsOf(t, tc.unseen) {
+				view.Unseen[c.Rank]++
+			}
+
+			in := Infer(view)
+			require.Len(t, in.Seats, 3)
+			assert.Equal(t, []card.Rank{card.Rank9}, in.Seats[tc.seat].Certain(), "own hand is known")
+			for seat, want := range tc.possible {
+				assert.Equal(t, want, record.FormatRanks(in.Seats[seat].Possible()), "possible for seat %d", seat)
+				assert.Equal(t, tc.certain[seat], record.FormatRanks(in.Seats[seat].Certain()), "certain for seat %d", seat)
+			}
+		})
+	}
+}
+
+// eagerAgent always beats when it can, so every pass it makes is forced.
+type eagerAgent struct{}
+
+func (eagerAgent) Bid(view game.PlayerView) game.BidAction {
+	return view.ValidBids[len(view.ValidBids)-1]
+}
+
+func (eagerAgent) Play(view game.PlayerView) rule.ParsedHand {
+	last := view.LastPlayedHand
+	if view.FreePlay() {
+		last = rule.ParsedHand{}
+	}
+	if plays := rule.EnumerateLegalPlays(view.Rules, view.Hand, last); len(plays) > 0 {
+		return plays[0]
+	}
+	return rule.ParsedHand{}
+}
+
+// TestInfer_Sound checks in whole games that every seat's real hand lies within the inferred bounds.
+func TestInfer_Sound(t *testing.T) {
+	for _, rules := range []rule.RuleSet{rule.Classic, rule.TwoPlayer, rule.FourPlayer} {
+		t.Run(rules.Name, func(t *testing.T) {
+			t.Parallel()
+			for seed := range int64(5) {
+				g := game.NewGameWithRules(seed, rules)
+				g.Deal()
+				g.Bidding()
+				for {
+					for seat := range g.Players {
+						in := Infer(g.View(seat))
+						for other, p := range g.Players {
+							var held card.RankCounts
+							for _, c := range p.Hand {
+								held[c.Rank]++
+							}
+							h := in.Seats[other]
+							for r := card.Rank3; r <= card.RankRedJoker; r++ {
+								require.GreaterOrEqual(t, held[r], h.Min[r], "seed %d seat %d sees seat %d rank %v", seed, seat, other, r)
+								require.LessOrEqual(t, held[r], h.Max[r], "seed %d seat %d sees seat %d rank %v", seed, seat, other, r)
+							}
+						}
+					}
+					if _, isOver := g.CheckWinner(); isOver {
+						break
+					}
+					require.NoError(t, g.Step(eagerAgent{}))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package infer
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// maxAttempts 按推断的上限发牌走进死路时最多重试的次数，之后只保留下限
+const maxAttempts = 20
+
+// Sampler 随机生成与公开信息一致的各座位手牌，用于估计概率和蒙特卡洛模拟
+// 每个对手的手牌都落在 Infer 推断出的上下界之内，地主还没打出的底牌一定在地主手里。
+type Sampler struct {
+	// Weight 可选的样本权重，反映叫地主和出牌的表现是否与样本相符；为 nil 时所有样本的权重相同
+	Weight func(hands [][]card.Card) float64
+
+	view   game.PlayerView
+	bounds *Inference
+	unseen card.RankCounts
+	hidden int // 不属于任何对手的牌的张数
+}
+
+// NewSampler 为 view 所在的座位创建一个抽样器
+func NewSampler(view game.PlayerView) *Sampler {
+	s := &Sampler{view: view, bounds: Infer(view)}
+	total := 0
+	for r, n := range view.Unseen {
+		s.unseen[r] = max(n, 0)
+		total += s.unseen[r]
+	}
+	s.hidden = total
+	for seat, size := range view.HandSizes {
+		if seat != view.Seat {
+			s.hidden -= size
+		}
+	}
+	s.hidden = max(s.hidden, 0)
+	return s
+}
+
+// Sample 随机生成一组手牌，下标是座位，自己的手牌原样保留
+// 不属于任何人的牌（例如两人玩法中不用的牌）不出现在结果中。
+func (s *Sampler) Sample(rng *rand.Rand) [][]card.Card {
+	for range maxAttempts {
+		if hands, ok := s.deal(rng, true); ok {
+			return hands
+		}
+	}
+	hands, _ := s.deal(rng, false)
+	return hands
+}
+
+// deal 先把一定有的牌发给各座位，其余的牌逐张发给还有空位的座位，选中每个座位的机会和它的空位数成正比，
+// 这和洗牌后依次发牌是一样的。capped 为 true 时不让任何座位超过推断的上限，走进死路时 ok 为 false。
+func (s *Sampler) deal(rng *rand.Rand, capped bool) ([][]card.Card, bool) {
+	n := len(s.view.HandSizes)
+	hands := make([][]card.Card, n)
+	hands[s.view.Seat] = slices.Clone(s.view.Hand)
+	held := make([]card.RankCounts, n)
+	room := make([]int, n+1) // 最后一个位置是不属于任何人的牌
+	room[n] = s.hidden
+
+	pool := s.unseen
+	for seat, size := range s.view.HandSizes {
+		if seat == s.view.Seat {
+			continue
+		}
+		room[seat] = size
+		for r := card.Rank3; r <= card.RankRedJoker; r++ {
+			m := min(s.bounds.Seats[seat].Min[r], pool[r], room[seat])
+			for range m {
+				hands[seat] = append(hands[seat], card.Card{Rank: r})
+			}
+			held[seat][r] += m
+			pool[r] -= m
+			room[seat] -= m
+		}
+	}
+
+	var cards []card.Card
+	for r := card.Rank3; r <= card.RankRedJoker; r++ {
+		for range pool[r] {
+			cards = append(cards, card.Card{Rank: r})
+		}
+	}
+	rng.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+
+	fits := func(slot int, r card.Rank) bool {
+		if room[slot] <= 0 {
+			return false
+		}
+		return !capped || slot == n || held[slot][r] < s.bounds.Seats[slot].Max[r]
+	}
+	for _, c := range cards {
+		total := 0
+		for slot := range room {
+			if slot != s.view.Seat && fits(slot, c.Rank) {
+				total += room[slot]
+			}
+		}
+		if total == 0 {
+			if capped {
+				return nil, false
+			}
+			break
+		}
+		x := rng.Intn(total)
+		for slot := range room {
+			if slot == s.view.Seat || !fits(slot, c.Rank) {
+				continue
+			}
+			if x < room[slot] {
+				if slot < n {
+					hands[slot] = append(hands[slot], c)
+					held[slot][c.Rank]++
+				}
+				room[slot]--
+				break
+			}
+			x -= room[slot]
+		}
+	}
+	return hands, true
+}
+
+// Event 对一次抽样的判断
+type Event func(hands [][]card.Card) bool
+
+// Probability 抽 n 个样本估计 event 成立的概率，设置了 Weight 时按权重计算
+func (s *Sampler) Probability(rng *rand.Rand, n int, event Event) float64 {
+	var hit, total float64
+	for range n {
+		hands := s.Sample(rng)
+		w := 1.0
+		if s.Weight != nil {
+			w = s.Weight(hands)
+		}
+		total += w
+		if event(hands) {
+			hit += w
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return hit / total
+}
+
+// HoldsBomb seat 手里有炸弹，癞子玩法中癞子可以凑成炸弹；王炸另算
+func HoldsBomb(rules rule.RuleSet, seat int) Event {
+	return func(hands [][]card.Card) bool {
+		var held card.RankCounts
+		for _, c := range hands[seat] {
+			held[c.Rank]++
+		}
+		wild := 0
+		if rules.WildCards && rules.Wild != 0 {
+			wild = held[rules.Wild]
+		}
+		for r := card.Rank3; r <= card.Rank2; r++ {
+			if held[r] >= 4 || (r != rules.Wild && held[r] > 0 && held[r]+wild >= 4) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HoldsRocket seat 手里有王炸，多副牌时要所有的王
+func HoldsRocket(rules rule.RuleSet, seat int) Event {
+	return func(hands [][]card.Card) bool {
+		var held card.RankCounts
+		for _, c := range hands[seat] {
+			held[c.Rank]++
+		}
+		decks := rules.DeckCount()
+		return held[card.RankBlackJoker] >= decks && held[card.RankRedJoker] >= decks
+	}
+}
+
+// RocketSplit 没有人能打出王炸：大小王分在不同的人手里，或者有的王不在任何人手里
+func RocketSplit(rules rule.RuleSet) Event {
+	return func(hands [][]card.Card) bool {
+		for seat := range hands {
+			if HoldsRocket(rules, seat)(hands) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOpponent view 所在座位的任何一个对手满足 event
+func AnyOpponent(view game.PlayerView, event func(seat int) Event) Event {
+	return func(hands [][]card.Card) bool {
+		for seat := range hands {
+			if !view.IsTeammate(seat) && event(seat)(hands) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// BidWeight 按叫地主的表现给样本加权：叫得越高的人越可能拿着大牌
+// 每个座位叫地主时的手牌由样本加上他打出过的牌还原，地主还要去掉底牌；
+// 按 rule.Evaluate 估计他应该叫到几分，和实际叫的相差一级权重减半。
+func BidWeight(view game.PlayerView) func(hands [][]card.Card) float64 {
+	bids := make(map[int]int)
+	for _, b := range view.Bids {
+		bids[b.Seat] = max(bids[b.Seat], bidLevel(b.Action))
+	}
+	var bottom card.RankCounts
+	for _, c := range view.BottomCards {
+		bottom[c.Rank]++
+	}
+	return func(hands [][]card.Card) float64 {
+		w := 1.0
+		for seat, level := range bids {
+			if seat == view.Seat || seat >= len(hands) {
+				continue
+			}
+			var held card.RankCounts
+			for _, c := range hands[seat] {
+				held[c.Rank]++
+			}
+			if seat < len(view.Played) {
+				for _, c := range view.Played[seat] {
+					held[c.Rank]++
+				}
+			}
+			if seat == view.LandlordSeat {
+				for r := range held {
+					held[r] = max(held[r]-bottom[r], 0)
+				}
+			}
+			want := rule.Evaluate(view.Rules, held.Cards()).BidLevel()
+			w *= math.Pow(0.5, math.Abs(float64(want-level)))
+		}
+		return w
+	}
+}
+
+// bidLevel 叫地主的动作相当于叫几分，不叫为 0
+func bidLevel(action game.BidAction) int {
+	switch action {
+	case game.BidOne, game.BidCall:
+		return 1
+	case game.BidTwo, game.BidRob:
+		return 2
+	case game.BidThree:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,143 @@
+package infer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSampler_Sample checks that sampled deals are consistent with the view.
+func TestSampler_Sample(t *testing.T) {
+	for _, rules := range []rule.RuleSet{rule.Classic, rule.TwoPlayer, rule.FourPlayer} {
+		t.Run(rules.Name, func(t *testing.T) {
+			t.Parallel()
+			g := game.NewGameWithRules(1, rules)
+			g.Deal()
+			g.StartBidding(0)
+			require.NoError(t, g.Bid(game.BidThree))
+			require.NoError(t, g.Play(g.TimeoutMove()))
+
+			view := g.View(1)
+			sampler := NewSampler(view)
+			rng := rand.New(rand.NewSource(1))
+			for range 50 {
+				hands := sampler.Sample(rng)
+
+				require.Len(t, hands, len(g.Players))
+				assert.Equal(t, view.Hand, hands[1], "own hand is never resampled")
+				var dealt card.RankCounts
+				for seat, h := range hands {
+					assert.Len(t, h, view.HandSizes[seat])
+					if seat != view.Seat {
+						for r, n := range card.CountRanks(h) {
+							dealt[r] += n
+						}
+					}
+				}
+
+				// 地主还没打出的底牌一定在地主手里
+				landlord, bottom := card.CountRanks(hands[0]), landlordBottom(view)
+				for r := card.Rank3; r <= card.RankRedJoker; r++ {
+					assert.GreaterOrEqual(t, landlord[r], bottom[r])
+					assert.LessOrEqual(t, dealt[r], view.Unseen[r], "rank %s", card.Rank(r))
+					if sampler.hidden == 0 {
+						assert.Equal(t, view.Unseen[r], dealt[r], "opponents hold every unseen %s", card.Rank(r))
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestSampler_Probability checks estimates against cases that can be counted by hand.
+func TestSampler_Probability(t *testing.T) {
+	view := func(unseen, bottom string, landlord int) func(t *testing.T) game.PlayerView {
+		return func(t *testing.T) game.PlayerView {
+			v := game.PlayerView{
+				Seat:         0,
+				Hand:         cardsOf(t, "9B"),
+				Phase:        game.PhasePlaying,
+				LandlordSeat: landlord,
+				HandSizes:    []int{2, 2, 2},
+				Unseen:       make(map[card.Rank]int),
+				Played:       make([][]card.Card, 3),
+				BottomCards:  cardsOf(t, bottom),
+				Rules:        rule.Classic,
+			}
+			for _, c := range cardsOf(t, unseen) {
+				v.Unseen[c.Rank]++
+			}
+			return v
+		}
+	}
+	bothThrees := func(hands [][]card.Card) bool { return card.CountRanks(hands[1])[card.Rank3] == 2 }
+
+	testCases := []struct {
+		name     string
+		view     func(t *testing.T) game.PlayerView
+		event    func(v game.PlayerView) Event
+		expected float64
+	}{
+		{
+			name:     "one deal in six gives seat 2 both threes",
+			view:     view("3355", "", 0),
+			event:    func(game.PlayerView) Event { return bothThrees },
+			expected: 1.0 / 6,
+		},
+		{
+			name:     "the rocket is split while I hold a joker",
+			view:     view("3355", "", 0),
+			event:    func(v game.PlayerView) Event { return RocketSplit(v.Rules) },
+			expected: 1,
+		},
+		{
+			name: "a bomb that must be with the landlord",
+			view: view("5555", "55", 1),
+			event: func(v game.PlayerView) Event {
+				return AnyOpponent(v, func(seat int) Event { return HoldsBomb(v.Rules, seat) })
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v := tc.view(t)
+			p := NewSampler(v).Probability(rand.New(rand.NewSource(1)), 3000, tc.event(v))
+			assert.InDelta(t, tc.expected, p, 0.03)
+		})
+	}
+}
+
+// TestBidWeight checks that a high bid makes strong holdings more likely for the bidder.
+func TestBidWeight(t *testing.T) {
+	t.Parallel()
+	view := game.PlayerView{
+		Seat:         0,
+		Hand:         cardsOf(t, "999"),
+		Phase:        game.PhaseBidding,
+		LandlordSeat: -1,
+		HandSizes:    []int{3, 3, 3},
+		Unseen:       make(map[card.Rank]int),
+		Bids:         []game.Bid{{Seat: 1, Action: game.BidThree}, {Seat: 2, Action: game.BidPass}},
+		Rules:        rule.Classic,
+	}
+	for _, c := range cardsOf(t, "345BR2") {
+		view.Unseen[c.Rank]++
+	}
+	rocket := HoldsRocket(view.Rules, 1)
+
+	sampler := NewSampler(view)
+	plain := sampler.Probability(rand.New(rand.NewSource(1)), 3000, rocket)
+	assert.InDelta(t, 0.2, plain, 0.03, "four of the twenty deals give seat 1 the rocket")
+
+	sampler.Weight = BidWeight(view)
+	weighted := sampler.Probability(rand.New(rand.NewSource(1)), 3000, rocket)
+	assert.Greater(t, weighted, plain+0.2)
+}
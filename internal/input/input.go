@@ -0,0 +1,51 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+)
+
+// ParseBid 把键盘输入解析为叫地主动作
+func ParseBid(input string) (game.BidAction, error) {
+	switch strings.ToUpper(strings.TrimSpace(input)) {
+	case "PASS", "0", "不叫", "不抢":
+		return game.BidPass, nil
+	case "1":
+		return game.BidOne, nil
+	case "2":
+		return game.BidTwo, nil
+	case "3":
+		return game.BidThree, nil
+	case "CALL", "叫", "叫地主":
+		return game.BidCall, nil
+	case "ROB", "抢", "抢地主":
+		return game.BidRob, nil
+	default:
+		return game.BidPass, fmt.Errorf("无法识别的叫地主指令: %s", input)
+	}
+}
+
+// IsPass 判断输入是否为 PASS
+func IsPass(input string) bool {
+	return strings.ToUpper(strings.TrimSpace(input)) == "PASS"
+}
+
+// ParsePlay 把键盘输入（如 33344、JOKER）解析为手牌中的牌，输入 PASS 时返回 nil，
+// 空输入返回错误，避免误按回车就让出这一轮
+func ParsePlay(input string, hand []card.Card) ([]card.Card, error) {
+	if IsPass(input) {
+		return nil, nil
+	}
+	if strings.TrimSpace(input) == "" {
+		return nil, errors.New("请输入要出的牌或 PASS")
+	}
+	cards, err := card.FindCardsInHand(hand, strings.ToUpper(strings.TrimSpace(input)))
+	if err != nil {
+		return nil, fmt.Errorf("出牌无效: %w", err)
+	}
+	return cards, nil
+}
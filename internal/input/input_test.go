@@ -0,0 +1,104 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseBid verifies the text accepted by the bidding prompt.
+func TestParseBid(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    game.BidAction
+		expectError bool
+	}{
+		{"pass", game.BidPass, false},
+		{"0", game.BidPass, false},
+		{"2", game.BidTwo, false},
+		{" 3 ", game.BidThree, false},
+		{"叫", game.BidCall, false},
+		{"rob", game.BidRob, false},
+		{"4", game.BidPass, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+			action, err := ParseBid(tc.input)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, action)
+		})
+	}
+}
+
+// TestParsePlay uses a table to test play parsing against a hand.
+func TestParsePlay(t *testing.T) {
+	hand := []card.Card{
+		{Rank: card.RankRedJoker, Suit: card.Joker},
+		{Rank: card.RankBlackJoker, Suit: card.Joker},
+		{Rank: card.Rank10, Suit: card.Spade},
+		{Rank: card.Rank3, Suit: card.Heart},
+		{Rank: card.Rank3, Suit: card.Spade},
+	}
+
+	testCases := []struct {
+		name        string
+		input       string
+		expected    []card.Rank
+		expectError bool
+	}{
+		{name: "pair", input: "33", expected: []card.Rank{card.Rank3, card.Rank3}},
+		{name: "ten written as 10", input: "10", expected: []card.Rank{card.Rank10}},
+		{name: "lower case and spaces", input: " t ", expected: []card.Rank{card.Rank10}},
+		{name: "rocket", input: "joker", expected: []card.Rank{card.RankBlackJoker, card.RankRedJoker}},
+		{name: "pass", input: "Pass", expected: nil},
+		{name: "not enough cards", input: "333", expectError: true},
+		{name: "unknown rank", input: "X", expectError: true},
+		{name: "empty", input: "", expectError: true},
+		{name: "whitespace only", input: "   ", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cards, err := ParsePlay(tc.input, hand)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var ranks []card.Rank
+			for _, c := range cards {
+				ranks = append(ranks, c.Rank)
+			}
+			assert.ElementsMatch(t, tc.expected, ranks)
+		})
+	}
+}
+
+// TestParsePlay_FourPlayerRocket checks that the JOKER shortcut enters the four-joker king bomb.
+func TestParsePlay_FourPlayerRocket(t *testing.T) {
+	hand := []card.Card{
+		{Rank: card.RankRedJoker, Suit: card.Joker},
+		{Rank: card.RankRedJoker, Suit: card.Joker},
+		{Rank: card.RankBlackJoker, Suit: card.Joker},
+		{Rank: card.RankBlackJoker, Suit: card.Joker},
+		{Rank: card.Rank3, Suit: card.Spade},
+	}
+	cards, err := ParsePlay("joker", hand)
+	require.NoError(t, err)
+	assert.Len(t, cards, 4)
+
+	hands, err := rule.ParseHand(rule.FourPlayer, cards)
+	require.NoError(t, err)
+	assert.Equal(t, rule.Rocket, hands[0].Type)
+}
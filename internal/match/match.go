@@ -0,0 +1,199 @@
+package match
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// Config 比赛的配置
+type Config struct {
+	Hands       int          // 比赛的局数，0 表示不限
+	TargetScore int          // 有玩家累计得分达到 ±TargetScore 时比赛结束，0 表示不限
+	Seed        int64        // 第一局的种子，后面每局的种子由它派生；为 0 时使用基于当前时间的种子
+	Rules       rule.RuleSet // 每一局使用的牌型规则，零值为经典规则
+}
+
+// HandResult 一局的结算记录
+type HandResult struct {
+	Seed       int64
+	First      int // 第一个叫地主的玩家
+	Landlord   int
+	Settlement game.Settlement
+}
+
+// Standing 一名玩家的累计成绩
+type Standing struct {
+	Seat   int
+	Name   string
+	Points int // 累计得分
+	Wins   int // 获胜的局数
+}
+
+// Match 由多局组成的一场比赛
+// 每局轮流由下一位玩家先叫地主，结算后的得分记入总账。
+type Match struct {
+	Config
+	Results []HandResult // 已经结束的每一局
+	Totals  []int        // 每个座位的累计得分
+
+	names   []string
+	rng     *rand.Rand
+	opener  int        // 第一局第一个叫地主的玩家，-1 表示还没开始
+	game    *game.Game // 当前这一局
+	first   int        // 当前这一局第一个叫地主的玩家
+	settled bool       // 当前这一局是否已经记账
+
+	subscribers    map[int]func(*game.Game, game.Event)
+	nextSubscriber int
+}
+
+// New 创建一场比赛
+func New(cfg Config) *Match {
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	return &Match{
+		Config: cfg,
+		rng:    rand.New(rand.NewSource(cfg.Seed)),
+		opener: -1,
+	}
+}
+
+// Resume 以读档恢复的一局作为第一局开始比赛，之后每局的种子由这一局的种子派生
+func Resume(cfg Config, g *game.Game) *Match {
+	cfg.Seed, cfg.Rules = g.Seed, g.Rules
+	m := New(cfg)
+	m.opener = g.CurrentTurn
+	if g.Auction != nil {
+		m.opener = g.Auction.First
+	}
+	m.Totals = make([]int, len(g.Players))
+	for _, p := range g.Players {
+		m.names = append(m.names, p.Name)
+	}
+	m.game, m.first = g, m.opener
+	return m
+}
+
+// NextGame 结算上一局，然后开始下一局：发牌并由轮到的玩家先叫地主
+func (m *Match) NextGame() (*game.Game, error) {
+	if m.game != nil {
+		if _, err := m.Settle(); err != nil {
+			return nil, err
+		}
+	}
+	if m.Over() {
+		return nil, errors.New("比赛已经结束")
+	}
+
+	seed := m.Seed
+	if len(m.Results) > 0 {
+		seed = m.rng.Int63()
+	}
+	g := game.NewGameWithRules(seed, m.Rules)
+	for id := range m.nextSubscriber {
+		m.attach(g, id) // 在发牌之前订阅，订阅者才能收到这一局的发牌事件
+	}
+	g.Deal()
+	if m.opener < 0 {
+		g.Bidding()
+		m.opener = g.Auction.First
+		m.Totals = make([]int, len(g.Players))
+		for _, p := range g.Players {
+			m.names = append(m.names, p.Name)
+		}
+	} else {
+		g.StartBidding((m.opener + len(m.Results)) % len(g.Players))
+	}
+	m.game, m.first, m.settled = g, g.Auction.First, false
+	return g, nil
+}
+
+// Subscribe 注册一个回调，当前这一局和之后每一局的事件都会连同所在的对局交给它
+// 之后的每一局在发牌之前就已经订阅，第一局的发牌事件也不会错过；返回取消订阅的函数。
+func (m *Match) Subscribe(fn func(*game.Game, game.Event)) (unsubscribe func()) {
+	if m.subscribers == nil {
+		m.subscribers = make(map[int]func(*game.Game, game.Event))
+	}
+	id := m.nextSubscriber
+	m.nextSubscriber++
+	m.subscribers[id] = fn
+	if m.game != nil {
+		m.attach(m.game, id)
+	}
+	return func() { delete(m.subscribers, id) }
+}
+
+// attach 把编号为 id 的订阅者挂到 g 上，取消订阅之后不再转发
+func (m *Match) attach(g *game.Game, id int) {
+	g.Subscribe(func(e game.Event) {
+		if fn, ok := m.subscribers[id]; ok {
+			fn(g, e)
+		}
+	})
+}
+
+// Settle 结算当前这一局并记入总账，同一局只会记一次
+func (m *Match) Settle() (game.Settlement, error) {
+	if m.game == nil {
+		return game.Settlement{}, errors.New("比赛还没有开始")
+	}
+	s, err := m.game.Settle()
+	if err != nil {
+		return game.Settlement{}, err
+	}
+	if m.settled {
+		return s, nil
+	}
+
+	m.settled = true
+	result := HandResult{Seed: m.game.Seed, First: m.first, Settlement: s}
+	for i, p := range m.game.Players {
+		if p.IsLandlord {
+			result.Landlord = i
+		}
+		m.Totals[i] += s.Points[i]
+	}
+	m.Results = append(m.Results, result)
+	return s, nil
+}
+
+// Over 比赛是否已经结束
+func (m *Match) Over() bool {
+	if m.Hands > 0 && len(m.Results) >= m.Hands {
+		return true
+	}
+	if m.TargetScore > 0 {
+		for _, total := range m.Totals {
+			if total >= m.TargetScore || total <= -m.TargetScore {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Standings 按累计得分从高到低排列的成绩表
+func (m *Match) Standings() []Standing {
+	standings := make([]Standing, len(m.Totals))
+	for i := range m.Totals {
+		standings[i] = Standing{Seat: i, Name: m.names[i], Points: m.Totals[i]}
+	}
+	for _, r := range m.Results {
+		for i, p := range r.Settlement.Points {
+			if p > 0 {
+				standings[i].Wins++
+			}
+		}
+	}
+	slices.SortStableFunc(standings, func(a, b Standing) int {
+		return cmp.Compare(b.Points, a.Points)
+	})
+	return standings
+}
@@ -0,0 +1,125 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playHand bids three on the first turn and finishes the hand with timeout moves.
+func playHand(t *testing.T, g *game.Game) {
+	t.Helper()
+	require.NoError(t, g.Bid(game.BidThree))
+	for {
+		if _, isOver := g.CheckWinner(); isOver {
+			return
+		}
+		if cards := g.TimeoutMove(); len(cards) > 0 {
+			require.NoError(t, g.Play(cards))
+		} else {
+			require.NoError(t, g.Pass())
+		}
+	}
+}
+
+// TestMatch_Hands plays a fixed number of hands and checks rotation and the ledger.
+func TestMatch_Hands(t *testing.T) {
+	m := New(Config{Hands: 4, Seed: 11})
+
+	var firsts []int
+	for !m.Over() {
+		g, err := m.NextGame()
+		require.NoError(t, err)
+		firsts = append(firsts, g.Auction.First)
+		if len(firsts) == 1 {
+			assert.Equal(t, int64(11), g.Seed, "the first hand uses the match seed")
+		}
+
+		_, err = m.NextGame()
+		assert.Error(t, err, "the hand is not finished yet")
+
+		playHand(t, g)
+		s, err := m.Settle()
+		require.NoError(t, err)
+		_, err = m.Settle()
+		require.NoError(t, err, "settling twice only counts once")
+		assert.Equal(t, s, m.Results[len(m.Results)-1].Settlement)
+	}
+
+	require.Len(t, m.Results, 4)
+	for i := 1; i < len(firsts); i++ {
+		assert.Equal(t, (firsts[i-1]+1)%3, firsts[i], "the first bidder rotates")
+	}
+
+	sum := 0
+	for seat, total := range m.Totals {
+		expected := 0
+		for _, r := range m.Results {
+			expected += r.Settlement.Points[seat]
+		}
+		assert.Equal(t, expected, total)
+		sum += total
+	}
+	assert.Zero(t, sum)
+
+	standings := m.Standings()
+	require.Len(t, standings, 3)
+	assert.GreaterOrEqual(t, standings[0].Points, standings[1].Points)
+	assert.GreaterOrEqual(t, standings[1].Points, standings[2].Points)
+
+	_, err := m.NextGame()
+	assert.Error(t, err, "the match is over")
+}
+
+// TestMatch_TargetScore ends the match once someone reaches the target.
+func TestMatch_TargetScore(t *testing.T) {
+	m := New(Config{TargetScore: 1, Seed: 3})
+	g, err := m.NextGame()
+	require.NoError(t, err)
+	assert.False(t, m.Over())
+
+	playHand(t, g)
+	_, err = m.Settle()
+	require.NoError(t, err)
+	assert.True(t, m.Over(), "any settled hand moves at least 3 points")
+}
+
+func TestResume(t *testing.T) {
+	g := game.NewGameWithSeed(5)
+	g.Deal()
+	g.StartBidding(1)
+	m := Resume(Config{Hands: 2}, g)
+	assert.Equal(t, int64(5), m.Seed)
+
+	playHand(t, g)
+	next, err := m.NextGame()
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.Auction.First, "the first bidder rotates from the resumed hand")
+	require.Len(t, m.Results, 1)
+	assert.Equal(t, 1, m.Results[0].First)
+}
+
+// TestMatch_Subscribe checks that subscribers see every hand from its deal onwards.
+func TestMatch_Subscribe(t *testing.T) {
+	m := New(Config{Hands: 2, Seed: 5})
+	var games []*game.Game
+	var deals int
+	m.Subscribe(func(g *game.Game, e game.Event) {
+		if d, ok := e.(game.Dealt); ok && !d.Redeal {
+			deals++
+			games = append(games, g)
+		}
+	})
+
+	first, err := m.NextGame()
+	require.NoError(t, err)
+	assert.Equal(t, 1, deals, "the first deal is published after subscribing")
+	playHand(t, first)
+
+	second, err := m.NextGame()
+	require.NoError(t, err)
+	assert.Equal(t, 2, deals)
+	assert.Equal(t, []*game.Game{first, second}, games)
+}
@@ -0,0 +1,109 @@
+package match
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+)
+
+// SaveVersion 比赛存档格式的版本，格式不兼容地改变时加一
+const SaveVersion = 1
+
+// saveFile 比赛存档的 JSON 格式，当前这一局按 game.Save 的格式嵌在 Game 中
+type saveFile struct {
+	Version     int             `json:"version"`
+	Hands       int             `json:"hands"`
+	TargetScore int             `json:"target_score"`
+	Seed        int64           `json:"seed"`
+	Names       []string        `json:"names"`
+	Opener      int             `json:"opener"`
+	First       int             `json:"first"`
+	Settled     bool            `json:"settled"`
+	Results     []HandResult    `json:"results"`
+	Totals      []int           `json:"totals"`
+	Game        json.RawMessage `json:"game"`
+}
+
+// Game 当前这一局，比赛还没有开始时为 nil
+func (m *Match) Game() *game.Game {
+	return m.game
+}
+
+// Save 把比赛的进度和当前这一局写成 JSON 存档
+func (m *Match) Save(w io.Writer) error {
+	if m.game == nil {
+		return errors.New("比赛还没有开始")
+	}
+	var buf bytes.Buffer
+	if err := m.game.Save(&buf); err != nil {
+		return err
+	}
+	f := saveFile{
+		Version:     SaveVersion,
+		Hands:       m.Hands,
+		TargetScore: m.TargetScore,
+		Seed:        m.Seed,
+		Names:       m.names,
+		Opener:      m.opener,
+		First:       m.first,
+		Settled:     m.settled,
+		Results:     m.Results,
+		Totals:      m.Totals,
+		Game:        buf.Bytes(),
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// Load 读取 Save 写出的存档，恢复比赛的进度和当前这一局
+// 只有一局的旧存档按 Resume 以 cfg 开始新的比赛。
+func Load(r io.Reader, cfg Config) (*Match, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取存档失败: %w", err)
+	}
+	var f saveFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("读取存档失败: %w", err)
+	}
+	if f.Game == nil {
+		g, err := game.Load(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return Resume(cfg, g), nil
+	}
+	if f.Version != SaveVersion {
+		return nil, fmt.Errorf("不支持的比赛存档版本 %d", f.Version)
+	}
+
+	g, err := game.Load(bytes.NewReader(f.Game))
+	if err != nil {
+		return nil, err
+	}
+	seats := len(g.Players)
+	if len(f.Totals) != seats || len(f.Names) != seats {
+		return nil, fmt.Errorf("存档中的比赛有 %d 名玩家，需要 %d 名", len(f.Totals), seats)
+	}
+	if f.Opener < 0 || f.Opener >= seats || f.First < 0 || f.First >= seats {
+		return nil, errors.New("存档中的座位不存在")
+	}
+
+	m := New(Config{Hands: f.Hands, TargetScore: f.TargetScore, Seed: f.Seed, Rules: g.Rules})
+	m.names, m.opener, m.first, m.settled = f.Names, f.Opener, f.First, f.Settled
+	m.Results, m.Totals, m.game = f.Results, f.Totals, g
+	// 第一局用比赛的种子，之后每开始一局取一次随机数，按已经开始的局数重放
+	started := len(m.Results)
+	if !m.settled {
+		started++
+	}
+	for range started - 1 {
+		m.rng.Int63()
+	}
+	return m, nil
+}
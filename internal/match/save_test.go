@@ -0,0 +1,72 @@
+package match
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveAndLoad round-trips a match through a save file.
+func saveAndLoad(t *testing.T, m *Match) *Match {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, m.Save(&buf))
+	loaded, err := Load(&buf, Config{})
+	require.NoError(t, err)
+	return loaded
+}
+
+// TestMatch_SaveLoad saves in the middle of a match and checks the loaded match finishes exactly like the original.
+func TestMatch_SaveLoad(t *testing.T) {
+	m := New(Config{Hands: 3, Seed: 9})
+	g, err := m.NextGame()
+	require.NoError(t, err)
+	playHand(t, g)
+	_, err = m.NextGame()
+	require.NoError(t, err)
+
+	loaded := saveAndLoad(t, m)
+	assert.Equal(t, m.Config, loaded.Config)
+	assert.Equal(t, m.Results, loaded.Results)
+	assert.Equal(t, m.Totals, loaded.Totals)
+	assert.Equal(t, m.Standings(), loaded.Standings())
+	assert.Equal(t, m.Game().Seed, loaded.Game().Seed)
+
+	// 两边从同一个局面打完剩下的两局
+	for _, mt := range []*Match{m, loaded} {
+		for !mt.Over() {
+			playHand(t, mt.Game())
+			if _, err := mt.NextGame(); err != nil {
+				_, err = mt.Settle()
+				require.NoError(t, err)
+			}
+		}
+	}
+	assert.Len(t, loaded.Results, 3, "the hand limit counts the hands played before saving")
+	assert.Equal(t, m.Results, loaded.Results)
+	assert.Equal(t, m.Totals, loaded.Totals)
+}
+
+// TestLoad_GameSave starts a new match from a save that only holds one hand.
+func TestLoad_GameSave(t *testing.T) {
+	g := game.NewGameWithSeed(5)
+	g.Deal()
+	g.StartBidding(1)
+	var buf bytes.Buffer
+	require.NoError(t, g.Save(&buf))
+
+	m, err := Load(&buf, Config{Hands: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, m.Hands)
+	assert.Equal(t, int64(5), m.Seed)
+	assert.Equal(t, []int{0, 0, 0}, m.Totals)
+	assert.Equal(t, 1, m.Game().Auction.First)
+}
+
+func TestMatch_SaveBeforeStart(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, New(Config{}).Save(&buf))
+}
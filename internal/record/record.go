@@ -0,0 +1,312 @@
+package record
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+)
+
+// 对局结果
+const (
+	ResultLandlord   = "landlord" // 地主胜
+	ResultFarmers    = "farmers"  // 农民胜
+	ResultUnfinished = "*"        // 对局未结束
+)
+
+// passNotation PASS 的记法
+const passNotation = "PASS"
+
+// Record 一局斗地主的棋谱
+// 文本格式仿照 PGN：先是若干行 [Key "Value"] 形式的头部，空一行后是出牌记录。
+// 座位在文本中从 1 开始编号，出牌使用与输入相同的点数记法（10 写作 T，王写作 B/R）。
+// 一手牌有多种牌型、打出的又不是默认的那种时，用 + 分开主体和带牌，例如 444555666+333；
+// 癞子代替别的点数时写成它代替的点数，后面加 *，例如 4567 和一张癞子组成的顺子 45678*：
+//
+//	[Player1 "Player 1 (你)"]
+//	[Player2 "Player 2"]
+//	[Player3 "Player 3"]
+//	[Seed "42"]
+//	[Rules "classic points"]
+//	[Bids "2:1 3:PASS 1:3"]
+//	[Landlord "1"]
+//	[Result "farmers"]
+//
+//	1. 1:33 2:PASS 3:55
+//	2. 1:PASS 2:TTJJQQ 3:PASS
+//	3. 1:444555666+333 2:PASS 3:PASS
+//	4. 1:45678* 2:PASS 3:PASS
+type Record struct {
+	Players  []string // 每个座位的玩家名字
+	Seed     int64    // 发牌使用的随机种子
+	Rules    string   // 规则，例如牌型规则和叫地主方式
+	Bids     []Bid    // 叫地主记录，包括重新发牌之前的
+	Landlord int      // 地主的座位，-1 表示尚未确定
+	Result   string   // 对局结果：ResultLandlord、ResultFarmers 或 ResultUnfinished
+	Moves    []Move   // 出牌记录
+}
+
+// Bid 一次叫地主，Action 使用叫地主的输入记法，例如 PASS、1、CALL、ROB
+type Bid struct {
+	Seat   int
+	Action string
+}
+
+// Move 一次出牌，Ranks 为空表示 PASS
+type Move struct {
+	Seat  int
+	Ranks []card.Rank
+	Body  int    // 非 0 时 Ranks 的前 Body 张是主体，其余是带牌，用于指明有歧义的牌型
+	Wild  []bool // 与 Ranks 一一对应，true 表示这一张是代替该点数的癞子；没有癞子代替时为 nil
+}
+
+// notation 出牌的记法
+func (m Move) notation() string {
+	switch {
+	case len(m.Ranks) == 0:
+		return passNotation
+	case m.Body > 0 && m.Body < len(m.Ranks):
+		return m.format(0, m.Body) + "+" + m.format(m.Body, len(m.Ranks))
+	default:
+		return m.format(0, len(m.Ranks))
+	}
+}
+
+// format 写出 Ranks[from:to]，癞子代替的点数后面加 *
+func (m Move) format(from, to int) string {
+	var b strings.Builder
+	for i := from; i < to; i++ {
+		b.WriteString(FormatRanks(m.Ranks[i : i+1]))
+		if i < len(m.Wild) && m.Wild[i] {
+			b.WriteByte('*')
+		}
+	}
+	return b.String()
+}
+
+// New 创建一个空棋谱
+func New() *Record {
+	return &Record{Landlord: -1, Result: ResultUnfinished}
+}
+
+// FormatRanks 把一组点数写成出牌记法
+func FormatRanks(ranks []card.Rank) string {
+	var b strings.Builder
+	for _, r := range ranks {
+		if r == card.Rank10 {
+			b.WriteByte('T')
+			continue
+		}
+		b.WriteString(r.String())
+	}
+	return b.String()
+}
+
+// ParseRanks 解析出牌记法，10 可以写作 T 或 10
+func ParseRanks(s string) ([]card.Rank, error) {
+	var ranks []card.Rank
+	for _, char := range strings.ReplaceAll(strings.ToUpper(s), "10", "T") {
+		r, err := card.RankFromChar(char)
+		if err != nil {
+			return nil, err
+		}
+		ranks = append(ranks, r)
+	}
+	if len(ranks) == 0 {
+		return nil, errors.New("出牌记录为空")
+	}
+	return ranks, nil
+}
+
+// String 返回棋谱的文本格式
+func (r *Record) String() string {
+	var b strings.Builder
+	_ = r.Write(&b)
+	return b.String()
+}
+
+// Write 以文本格式写出棋谱
+func (r *Record) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	header := func(key, value string) {
+		fmt.Fprintf(bw, "[%s %s]\n", key, strconv.Quote(value))
+	}
+	for i, name := range r.Players {
+		header(fmt.Sprintf("Player%d", i+1), name)
+	}
+	header("Seed", strconv.FormatInt(r.Seed, 10))
+	header("Rules", r.Rules)
+	bids := make([]string, len(r.Bids))
+	for i, bid := range r.Bids {
+		bids[i] = fmt.Sprintf("%d:%s", bid.Seat+1, bid.Action)
+	}
+	header("Bids", strings.Join(bids, " "))
+	header("Landlord", strconv.Itoa(r.Landlord+1))
+	header("Result", r.Result)
+
+	// 每行一圈，行首是圈数
+	seats := max(len(r.Players), 1)
+	for i, m := range r.Moves {
+		if i%seats == 0 {
+			fmt.Fprintf(bw, "\n%d.", i/seats+1)
+		}
+		fmt.Fprintf(bw, " %d:%s", m.Seat+1, m.notation())
+	}
+	if len(r.Moves) > 0 {
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Parse 从文本格式读取棋谱
+func Parse(rd io.Reader) (*Record, error) {
+	r := New()
+	scanner := bufio.NewScanner(rd)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var err error
+		if strings.HasPrefix(text, "[") {
+			err = r.parseHeader(text)
+		} else {
+			err = r.parseMoves(text)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("棋谱第 %d 行: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ParseString 从字符串读取棋谱
+func ParseString(s string) (*Record, error) {
+	return Parse(strings.NewReader(s))
+}
+
+func (r *Record) parseHeader(text string) error {
+	if !strings.HasSuffix(text, "]") {
+		return fmt.Errorf("头部缺少 ]: %s", text)
+	}
+	key, quoted, ok := strings.Cut(text[1:len(text)-1], " ")
+	if !ok {
+		return fmt.Errorf("头部格式错误: %s", text)
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return fmt.Errorf("头部 %s 的值格式错误: %w", key, err)
+	}
+
+	switch {
+	case strings.HasPrefix(key, "Player"):
+		n, err := strconv.Atoi(strings.TrimPrefix(key, "Player"))
+		if err != nil || n < 1 {
+			return fmt.Errorf("无法识别的玩家: %s", key)
+		}
+		for len(r.Players) < n {
+			r.Players = append(r.Players, "")
+		}
+		r.Players[n-1] = value
+	case key == "Seed":
+		r.Seed, err = strconv.ParseInt(value, 10, 64)
+	case key == "Rules":
+		r.Rules = value
+	case key == "Bids":
+		for _, field := range strings.Fields(value) {
+			seat, action, err := parseSeat(field)
+			if err != nil {
+				return err
+			}
+			r.Bids = append(r.Bids, Bid{Seat: seat, Action: action})
+		}
+	case key == "Landlord":
+		var n int
+		n, err = strconv.Atoi(value)
+		r.Landlord = n - 1
+	case key == "Result":
+		switch value {
+		case ResultLandlord, ResultFarmers, ResultUnfinished:
+			r.Result = value
+		default:
+			err = fmt.Errorf("无法识别的结果: %s", value)
+		}
+	}
+	// 不认识的头部直接忽略，方便以后扩展
+	if err != nil {
+		return fmt.Errorf("头部 %s: %w", key, err)
+	}
+	return nil
+}
+
+func (r *Record) parseMoves(text string) error {
+	for _, field := range strings.Fields(text) {
+		if strings.HasSuffix(field, ".") {
+			continue // 圈数
+		}
+		seat, notation, err := parseSeat(field)
+		if err != nil {
+			return err
+		}
+		move := Move{Seat: seat}
+		if strings.ToUpper(notation) != passNotation {
+			body, kickers, split := strings.Cut(notation, "+")
+			if err := move.parseRanks(body); err != nil {
+				return err
+			}
+			if split {
+				move.Body = len(move.Ranks)
+				if err := move.parseRanks(kickers); err != nil {
+					return err
+				}
+			}
+			if !slices.Contains(move.Wild, true) {
+				move.Wild = nil
+			}
+		}
+		r.Moves = append(r.Moves, move)
+	}
+	return nil
+}
+
+// parseRanks 解析可能带有 * 的出牌记法并追加到 Ranks，* 表示前一张是代替该点数的癞子
+func (m *Move) parseRanks(s string) error {
+	for _, part := range strings.SplitAfter(s, "*") {
+		if part == "" && len(m.Ranks) > 0 {
+			continue // 记法以 * 结尾
+		}
+		ranks, err := ParseRanks(strings.TrimSuffix(part, "*"))
+		if err != nil {
+			return err
+		}
+		m.Ranks = append(m.Ranks, ranks...)
+		for i := range ranks {
+			m.Wild = append(m.Wild, i == len(ranks)-1 && strings.HasSuffix(part, "*"))
+		}
+	}
+	return nil
+}
+
+// parseSeat 解析 "座位:内容"，座位从 1 开始编号
+func parseSeat(field string) (int, string, error) {
+	s, rest, ok := strings.Cut(field, ":")
+	if !ok {
+		return 0, "", fmt.Errorf("缺少座位: %s", field)
+	}
+	seat, err := strconv.Atoi(s)
+	if err != nil || seat < 1 {
+		return 0, "", fmt.Errorf("无法识别的座位: %s", field)
+	}
+	return seat - 1, rest, nil
+}
@@ -0,0 +1,103 @@
+package record
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `[Player1 "Player 1 (你)"]
+[Player2 "Player 2"]
+[Player3 "Player 3"]
+[Seed "42"]
+[Rules "points"]
+[Bids "2:1 3:PASS 1:3"]
+[Landlord "1"]
+[Result "farmers"]
+
+1. 1:33 2:PASS 3:55
+2. 1:PASS 2:TTJJQQ 3:PASS
+3. 1:BR 2:PASS 3:PASS
+4. 1:444555666+333 2:PASS 3:PASS
+5. 1:45678*
+`
+
+// TestParse checks every header and move of a sample record.
+func TestParse(t *testing.T) {
+	rec, err := ParseString(sample)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Player 1 (你)", "Player 2", "Player 3"}, rec.Players)
+	assert.Equal(t, int64(42), rec.Seed)
+	assert.Equal(t, "points", rec.Rules)
+	assert.Equal(t, []Bid{{1, "1"}, {2, "PASS"}, {0, "3"}}, rec.Bids)
+	assert.Equal(t, 0, rec.Landlord)
+	assert.Equal(t, ResultFarmers, rec.Result)
+
+	require.Len(t, rec.Moves, 13)
+	assert.Equal(t, Move{Seat: 0, Ranks: []card.Rank{card.Rank3, card.Rank3}}, rec.Moves[0])
+	assert.Equal(t, Move{Seat: 1}, rec.Moves[1])
+	assert.Equal(t, []card.Rank{card.Rank10, card.Rank10, card.RankJ, card.RankJ, card.RankQ, card.RankQ}, rec.Moves[4].Ranks)
+	assert.Equal(t, []card.Rank{card.RankBlackJoker, card.RankRedJoker}, rec.Moves[6].Ranks)
+	assert.Equal(t, 9, rec.Moves[9].Body, "the plane body comes before the kickers")
+	assert.Len(t, rec.Moves[9].Ranks, 12)
+	assert.Equal(t, Move{
+		Ranks: []card.Rank{card.Rank4, card.Rank5, card.Rank6, card.Rank7, card.Rank8},
+		Wild:  []bool{false, false, false, false, true},
+	}, rec.Moves[12], "the 8 is a wildcard")
+}
+
+// TestRoundTrip checks that writing a parsed record gives back the same text.
+func TestRoundTrip(t *testing.T) {
+	rec, err := ParseString(sample)
+	require.NoError(t, err)
+	assert.Equal(t, sample, rec.String())
+
+	again, err := ParseString(rec.String())
+	require.NoError(t, err)
+	assert.Equal(t, rec, again)
+
+	empty := New()
+	parsed, err := ParseString(empty.String())
+	require.NoError(t, err)
+	assert.Equal(t, -1, parsed.Landlord)
+	assert.Equal(t, ResultUnfinished, parsed.Result)
+}
+
+// TestParse_Errors uses a table to test malformed records.
+func TestParse_Errors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"unterminated header", `[Seed "42"`},
+		{"unquoted header value", `[Seed 42]`},
+		{"bad seed", `[Seed "x"]`},
+		{"bad result", `[Result "draw"]`},
+		{"move without seat", "1. 33"},
+		{"bad seat", "1. 0:33"},
+		{"bad rank", "1. 1:3X"},
+		{"wildcard marker without a rank", "1. 1:*3"},
+		{"bid without seat", `[Bids "PASS"]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseString(tc.input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestParseRanks checks the rank notation in both directions.
+func TestParseRanks(t *testing.T) {
+	ranks, err := ParseRanks("10JQKA")
+	require.NoError(t, err)
+	assert.Equal(t, "TJQKA", FormatRanks(ranks))
+
+	_, err = ParseRanks("")
+	assert.Error(t, err)
+}
@@ -7,17 +7,20 @@ import (
 	"github.com/palemoky/fight-the-landlord-go/internal/card"
 )
 // hasWinningBombOrRocket checks for any bomb or rocket that can beat the opponent's hand.
-func hasWinningBombOrRocket(analysis HandAnalysis, opponentHand ParsedHand) bool {
+func hasWinningBombOrRocket(rules RuleSet, analysis HandAnalysis, opponentHand ParsedHand) bool {
 	// Check for a winning Rocket.
-	if analysis.counts[card.RankBlackJoker] >= 1 && analysis.counts[card.RankRedJoker] >= 1 {
+	if decks := rules.DeckCount(); analysis.counts[card.RankBlackJoker] >= decks && analysis.counts[card.RankRedJoker] >= decks {
 		// A Rocket beats anything.
 		return true
 	}
 
-	// Check for a winning Bomb.
-	for _, r := range analysis.fours {
-		myBomb, _ := ParseHand([]card.Card{{Rank: r}, {Rank: r}, {Rank: r}, {Rank: r}})
-		if CanBeat(myBomb, opponentHand) {
+	// Check for a winning Bomb, using every card of the rank.
+	for r, count := range analysis.counts {
+		if count < 4 || r > card.Rank2 {
+			continue
+		}
+		myBomb := ParsedHand{Type: Bomb, KeyRank: r, Length: count}
+		if CanBeat(rules, myBomb, opponentHand) {
 			return true
 		}
 	}
@@ -44,30 +47,11 @@ func findWinningPair(analysis HandAnalysis, opponentHand ParsedHand) bool {
 	return false
 }
 
-// findWinningTrio checks for trios with or without kickers.
-// kickerType: 0=none, 1=single, 2=pair.
-func findWinningTrio(analysis HandAnalysis, opponentHand ParsedHand, kickerType int) bool {
+// findWinningTrio checks for a higher trio without kickers.
+func findWinningTrio(analysis HandAnalysis, opponentHand ParsedHand) bool {
 	for r, count := range analysis.counts {
 		if count >= 3 && r > opponentHand.KeyRank {
-			// Found a higher trio. Now check if we have enough cards for kickers.
-			remainingCards := len(analysis.ones) + len(analysis.pairs)*2 + len(analysis.trios)*3 + len(analysis.fours)*4 - 3
-			switch kickerType {
-			case 0: // No kicker needed
-				return true
-			case 1: // Need one single
-				if remainingCards >= 1 {
-					return true
-				}
-			case 2: // Need one pair
-				if remainingCards < 2 {
-					continue
-				}
-				// Check if the remaining cards contain a pair.
-				// This is true if there's any other pair/trio/four, or if the current trio came from a four.
-				if len(analysis.pairs) > 0 || len(analysis.trios) > 1 || len(analysis.fours) > 1 || (count == 4) {
-					return true
-				}
-			}
+			return true // Found a higher trio.
 		}
 	}
 	return false
@@ -137,9 +121,8 @@ func findWinningPairStraight(analysis HandAnalysis, opponentHand ParsedHand) boo
 	return false
 }
 
-// findWinningPlane checks for a winning plane with or without kickers.
-// kickerType: 0=none, 1=singles, 2=pairs.
-func findWinningPlane(analysis HandAnalysis, opponentHand ParsedHand, kickerType int) bool {
+// findWinningPlane checks for a winning plane without kickers.
+func findWinningPlane(analysis HandAnalysis, opponentHand ParsedHand) bool {
 	length := opponentHand.Length
 
 	var trioRanks []card.Rank
@@ -162,46 +145,8 @@ func findWinningPlane(analysis HandAnalysis, opponentHand ParsedHand, kickerType
 				break
 			}
 		}
-
 		if isPlane && trioRanks[i] > opponentHand.KeyRank {
-			// Found a higher plane. Now check for kickers.
-			totalCardsInHand := 0
-			for _, c := range analysis.counts {
-				totalCardsInHand += c
-			}
-			remainingCardCount := totalCardsInHand - (length * 3)
-
-			switch kickerType {
-			case 0: // No kickers
-				return true
-			case 1: // Need N singles
-				if remainingCardCount >= length {
-					return true
-				}
-			case 2: // Need N pairs
-				if remainingCardCount < length*2 {
-					continue
-				}
-				// This is a complex check. A simplified but effective heuristic:
-				// Count how many pairs can be formed from the rest of the hand.
-				kickerPairs := 0
-				for r, count := range analysis.counts {
-					// Is this rank part of the plane we just found?
-					isPlaneRank := false
-					for k := range length {
-						if trioRanks[i+k] == r {
-							isPlaneRank = true
-							break
-						}
-					}
-					if !isPlaneRank {
-						kickerPairs += count / 2
-					}
-				}
-				if kickerPairs >= length {
-					return true
-				}
-			}
+			return true
 		}
 	}
 	return false
@@ -0,0 +1,299 @@
+package rule
+
+import (
+	"slices"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+)
+
+// Decompose 把手牌拆成出完所需手数最少的一组牌型
+// 手数相同时依次比较：单独打出的单张更少、单独打出的对子更少、单独打出的单张和对子点数更大。
+// 会考虑顺子、连对、飞机带翅膀和三带，炸弹和王炸保持完整，不拆进别的牌型，也不作为四带二打出；
+// 癞子只当作本身的点数。结果中先是顺子、连对、飞机和三带，然后是对子和单张，最后是炸弹和王炸，同类按点数从小到大。
+func Decompose(rules RuleSet, hand []card.Card) []ParsedHand {
+	counts := card.CountRanks(hand)
+	d := &decomposer{rules: rules, memo: make(map[decomposeKey]decomposeStep)}
+	d.search(card.Rank3, counts, 0)
+
+	pool := make(map[card.Rank][]card.Card)
+	for _, c := range hand {
+		pool[c.Rank] = append(pool[c.Rank], c)
+	}
+	take := func(ranks []card.Rank) []card.Card {
+		cards := make([]card.Card, len(ranks))
+		for i, r := range ranks {
+			cards[i] = pool[r][0]
+			pool[r] = pool[r][1:]
+			if rules.IsWild(cards[i]) {
+				cards[i].As = cards[i].Rank
+			}
+		}
+		return cards
+	}
+	var plays []ParsedHand
+	parse := func(cards []card.Card) bool {
+		hands, err := ParseHand(rules, cards)
+		if err == nil {
+			plays = append(plays, hands[0])
+		}
+		return err == nil
+	}
+	for _, g := range d.groups(counts) {
+		body, kickers := take(g.body), take(g.kickers)
+		if parse(append(slices.Clone(body), kickers...)) {
+			continue
+		}
+		// 这套规则不允许的组合（例如两副牌中同色的两张王）拆开打出
+		if !parse(body) {
+			kickers = append(body, kickers...)
+		}
+		for _, c := range kickers {
+			parse([]card.Card{c})
+		}
+	}
+	return plays
+}
+
+// group 拆出的一手牌，带牌和主体分开记录
+type group struct {
+	body, kickers []card.Rank
+}
+
+// chain 以 start 开头的连续牌型：width 为 1、2、3 时分别是顺子、连对和飞机的机身，为 0 表示不以 start 开头
+type chain struct {
+	start         card.Rank
+	width, length int
+}
+
+// ranks 连续牌型用到的点数
+func (c chain) ranks() []card.Rank {
+	var ranks []card.Rank
+	for i := range c.length {
+		ranks = append(ranks, repeatRank(c.start+card.Rank(i), c.width)...)
+	}
+	return ranks
+}
+
+// decomposeCost 拆法的代价，逐项比较，越小越好
+type decomposeCost struct {
+	plays    int // 手数
+	singles  int // 单独打出的单张
+	pairs    int // 单独打出的对子
+	weakness int // 单独打出的单张和对子有多小
+}
+
+func (c decomposeCost) less(other decomposeCost) bool {
+	if c.plays != other.plays {
+		return c.plays < other.plays
+	}
+	if c.singles != other.singles {
+		return c.singles < other.singles
+	}
+	if c.pairs != other.pairs {
+		return c.pairs < other.pairs
+	}
+	return c.weakness < other.weakness
+}
+
+// decomposeKey 搜索状态
+// 搜索只改变 3 到 A 的张数，ranks 从低位起每 4 位是一个点数的张数，最高 4 位是开始的点数；
+// planes 记录已经选出、还没配带牌的飞机机身，每个占 8 位：起点和长度各 4 位。
+type decomposeKey struct {
+	ranks, planes uint64
+}
+
+func newDecomposeKey(from card.Rank, counts card.RankCounts, planes uint64) decomposeKey {
+	key := decomposeKey{ranks: uint64(from-card.Rank3) << 60, planes: planes}
+	for r := card.Rank3; r < card.Rank2; r++ {
+		key.ranks |= uint64(counts[r]&0xf) << (4 * (r - card.Rank3))
+	}
+	return key
+}
+
+// decomposeStep 一个搜索状态的最优代价和这一步的选择
+type decomposeStep struct {
+	cost   decomposeCost
+	choice chain
+}
+
+// decomposer 从小到大逐个点数决定以它开头的连续牌型，剩下的牌再组成三带、对子和单张
+// 搜索只记录每个状态的最优选择，拆法最后按记录还原。
+type decomposer struct {
+	rules RuleSet
+	memo  map[decomposeKey]decomposeStep
+}
+
+// next 从 from 开始第一个可以作为连续牌型起点的点数，炸弹不拆
+func (d *decomposer) next(from card.Rank, counts card.RankCounts) card.Rank {
+	for from < card.Rank2 && (counts[from] == 0 || counts[from] >= 4) {
+		from++
+	}
+	return from
+}
+
+// search 返回在 from 及更大的点数上选择连续牌型能得到的最小代价
+func (d *decomposer) search(from card.Rank, counts card.RankCounts, planes uint64) decomposeCost {
+	r := d.next(from, counts)
+	if r >= card.Rank2 {
+		cost, _ := d.finish(counts, planes, false)
+		return cost
+	}
+	key := newDecomposeKey(r, counts, planes)
+	if step, ok := d.memo[key]; ok {
+		return step.cost
+	}
+
+	best := decomposeStep{cost: d.search(r+1, counts, planes), choice: chain{start: r}}
+	for _, c := range []chain{{r, 3, 2}, {r, 2, 3}, {r, 1, d.rules.MinStraightLen()}} {
+		n := 0
+		for x := r; x < card.Rank2 && counts[x] >= c.width && counts[x] < 4; x++ {
+			n++
+		}
+		if n < c.length {
+			continue
+		}
+		next := counts
+		for i := range c.length - 1 {
+			next[r+card.Rank(i)] -= c.width
+		}
+		for ; c.length <= n; c.length++ {
+			next[r+card.Rank(c.length-1)] -= c.width
+			var cost decomposeCost
+			if c.width == 3 {
+				// 飞机的带牌要等所有的牌都分好之后再配
+				cost = d.search(r, next, planes<<8|uint64(r-card.Rank3)<<4|uint64(c.length))
+			} else {
+				cost = d.search(r, next, planes)
+				cost.plays++
+			}
+			if cost.less(best.cost) {
+				best = decomposeStep{cost: cost, choice: c}
+			}
+		}
+	}
+	d.memo[key] = best
+	return best.cost
+}
+
+// groups 按搜索记录的选择还原最优拆法
+func (d *decomposer) groups(counts card.RankCounts) []group {
+	var groups []group
+	from, planes := card.Rank3, uint64(0)
+	for {
+		r := d.next(from, counts)
+		if r >= card.Rank2 {
+			_, rest := d.finish(counts, planes, true)
+			return append(groups, rest...)
+		}
+		c := d.memo[newDecomposeKey(r, counts, planes)].choice
+		if c.width == 0 {
+			from = r + 1
+			continue
+		}
+		for i := range c.length {
+			counts[r+card.Rank(i)] -= c.width
+		}
+		if c.width == 3 {
+			planes = planes<<8 | uint64(r-card.Rank3)<<4 | uint64(c.length)
+		} else {
+			groups = append(groups, group{body: c.ranks()})
+		}
+		from = r
+	}
+}
+
+// finish 把没有组成顺子和连对的牌组成飞机、三带、对子、单张、炸弹和王炸，build 为 false 时只计算代价
+// 飞机和三张按机身从长到短配带牌：单张够用时带单张，否则带对子，都从最小的选起。
+func (d *decomposer) finish(counts card.RankCounts, planes uint64, build bool) (decomposeCost, []group) {
+	var cost decomposeCost
+	var bombs []group
+	for r := card.Rank3; r <= card.Rank2; r++ {
+		if counts[r] >= 4 {
+			cost.plays++
+			if build {
+				bombs = append(bombs, group{body: repeatRank(r, counts[r])})
+			}
+			counts[r] = 0
+		}
+	}
+	if decks := d.rules.DeckCount(); counts[card.RankBlackJoker] >= decks && counts[card.RankRedJoker] >= decks {
+		counts[card.RankBlackJoker] -= decks
+		counts[card.RankRedJoker] -= decks
+		cost.plays++
+		if build {
+			bombs = append(bombs, group{body: append(repeatRank(card.RankBlackJoker, decks), repeatRank(card.RankRedJoker, decks)...)})
+		}
+	}
+
+	// 机身按长度从长到短排列，飞机在前，三张在后
+	var bodies [card.RankRedJoker + 1]chain
+	n := 0
+	for ; planes != 0; planes >>= 8 {
+		c := chain{start: card.Rank3 + card.Rank(planes>>4&0xf), width: 3, length: int(planes & 0xf)}
+		i := n
+		for ; i > 0 && bodies[i-1].length < c.length; i-- {
+			bodies[i] = bodies[i-1]
+		}
+		bodies[i] = c
+		n++
+	}
+	var singles, pairs [card.RankRedJoker + 1]card.Rank
+	ns, np := 0, 0
+	for r := card.Rank3; r <= card.RankRedJoker; r++ {
+		switch counts[r] {
+		case 3:
+			bodies[n] = chain{start: r, width: 3, length: 1}
+			n++
+		case 2:
+			pairs[np] = r
+			np++
+		case 1:
+			singles[ns] = r
+			ns++
+		}
+	}
+
+	var result []group
+	si, pi := 0, 0
+	for _, b := range bodies[:n] {
+		g := group{}
+		switch {
+		case ns-si >= b.length:
+			if build {
+				g.kickers = slices.Clone(singles[si : si+b.length])
+			}
+			si += b.length
+		case np-pi >= b.length:
+			if build {
+				for _, r := range pairs[pi : pi+b.length] {
+					g.kickers = append(g.kickers, r, r)
+				}
+			}
+			pi += b.length
+		}
+		if build {
+			g.body = b.ranks()
+			result = append(result, g)
+		}
+	}
+	cost.plays += n + (np - pi) + (ns - si)
+	cost.singles, cost.pairs = ns-si, np-pi
+	for _, r := range pairs[pi:np] {
+		cost.weakness += int(card.RankRedJoker - r)
+	}
+	for _, r := range singles[si:ns] {
+		cost.weakness += int(card.RankRedJoker - r)
+	}
+	if !build {
+		return cost, nil
+	}
+
+	slices.SortStableFunc(result, func(a, b group) int { return int(a.body[0]) - int(b.body[0]) })
+	for _, r := range pairs[pi:np] {
+		result = append(result, group{body: []card.Rank{r, r}})
+	}
+	for _, r := range singles[si:ns] {
+		result = append(result, group{body: []card.Rank{r}})
+	}
+	return cost, append(result, bombs...)
+}
@@ -0,0 +1,79 @@
+package rule
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ranksOf parses compact rank notation such as "3455TJ2BR", T is ten, B and R are the jokers.
+func ranksOf(s string) []card.Rank {
+	var ranks []card.Rank
+	for _, ch := range s {
+		ranks = append(ranks, card.Rank3+card.Rank(strings.IndexRune("3456789TJQKA2BR", ch)))
+	}
+	return ranks
+}
+
+// TestDecompose checks that hands are split into the fewest plays with the expected tie-breakers.
+func TestDecompose(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hand     string
+		expected []string
+	}{
+		{name: "straight, pair and single", hand: "34567KK2", expected: []string{"34567", "KK", "2"}},
+		{name: "trio takes the smallest single", hand: "99942", expected: []string{"9994", "2"}},
+		{name: "bomb and rocket are kept whole", hand: "8888BR3", expected: []string{"3", "8888", "BR"}},
+		{name: "plane with pairs", hand: "55566699JJ", expected: []string{"55566699JJ"}},
+		{name: "a shorter straight leaves a pair instead of a single", hand: "34567899", expected: []string{"345678", "99"}},
+		{name: "two straights share a rank", hand: "34567789TJ", expected: []string{"34567", "789TJ"}},
+		{name: "pairs join into a pair straight", hand: "334455", expected: []string{"334455"}},
+		{name: "a straight can take one card of a trio", hand: "333456799", expected: []string{"34567", "33", "99"}},
+		{name: "bombs are held back from straights", hand: "34566667", expected: []string{"3", "4", "5", "7", "6666"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			plays := Decompose(Classic, testRuleCards(ranksOf(tc.hand)...))
+
+			var expected [][]card.Rank
+			for _, s := range tc.expected {
+				expected = append(expected, ranksOf(s))
+			}
+			assert.Equal(t, expected, playRanks(plays))
+		})
+	}
+}
+
+// TestDecompose_Consistency checks on random deals that every card lands in exactly one legal play.
+func TestDecompose_Consistency(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewSource(1))
+	laizi := Laizi
+	laizi.Wild = card.Rank7
+	for _, rules := range []RuleSet{Classic, FourPlayer, TwoPlayer, laizi} {
+		for range 50 {
+			deck := rules.NewDeck()
+			rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+			hand := deck[:rules.HandSize()+rules.BottomCount()]
+
+			plays := Decompose(rules, hand)
+			var used []card.Card
+			for _, p := range plays {
+				_, err := ParseHand(rules, p.Cards)
+				require.NoError(t, err)
+				for _, c := range p.Cards {
+					c.As = 0 // 癞子拆牌时当作本身的点数
+					used = append(used, c)
+				}
+			}
+			assert.ElementsMatch(t, hand, used, "%s", rules.Name)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package rule
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+)
+
+// EnumerateLegalPlays 列出手牌中所有按 rules 能打过 last 的不同出法
+// last 为空时表示自由出牌，此时列出手牌能组成的全部牌型。
+// 三带、飞机和四带二会展开所有带牌的选择，炸弹和王炸总会被考虑在内。
+// 点数组成相同的一组牌只会列出一次，但它的每种合法解释（例如飞机和飞机带单）都会分别列出。
+// 癞子玩法中癞子可以补上主体缺少的牌，作为带牌时只当作本身的点数。
+func EnumerateLegalPlays(rules RuleSet, hand []card.Card, last ParsedHand) []ParsedHand {
+	e := newEnumerator(rules, hand)
+
+	var candidates [][]card.Rank
+	if last.IsEmpty() {
+		candidates = e.allCombos()
+	} else {
+		candidates = e.combosOfType(last.Type, last.Length)
+	}
+	candidates = append(candidates, e.bombs()...)
+	candidates = append(candidates, e.rocket()...)
+
+	seen := make(map[string]bool, len(candidates))
+	var plays []ParsedHand
+	for _, ranks := range candidates {
+		key := rankKey(ranks)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cards, ok := e.pick(ranks)
+		if !ok {
+			continue
+		}
+		// 候选的带牌可能不符合规则，由 ParseHand 过滤
+		hands, err := ParseHand(rules, cards)
+		if err != nil {
+			continue
+		}
+		for _, parsed := range hands {
+			if last.IsEmpty() || CanBeat(rules, parsed, last) {
+				plays = append(plays, parsed)
+			}
+		}
+	}
+
+	slices.SortStableFunc(plays, func(a, b ParsedHand) int {
+		return cmp.Or(
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Length, b.Length),
+			cmp.Compare(a.KeyRank, b.KeyRank),
+			cmp.Compare(len(a.Cards), len(b.Cards)),
+		)
+	})
+	return plays
+}
+
+// enumerator 按点数对手牌分组，负责生成候选出法的点数组合
+type enumerator struct {
+	rules  RuleSet
+	counts map[card.Rank]int
+	byRank map[card.Rank][]card.Card
+	ranks  []card.Rank // 手牌中出现过的点数，从小到大
+	wild   card.Rank   // 癞子的点数，手牌中没有癞子时为 0
+}
+
+func newEnumerator(rules RuleSet, hand []card.Card) *enumerator {
+	e := &enumerator{
+		rules:  rules,
+		counts: make(map[card.Rank]int),
+		byRank: make(map[card.Rank][]card.Card),
+	}
+	for _, c := range hand {
+		e.counts[c.Rank]++
+		e.byRank[c.Rank] = append(e.byRank[c.Rank], c)
+	}
+	for r := range e.counts {
+		e.ranks = append(e.ranks, r)
+	}
+	slices.Sort(e.ranks)
+	if e.counts[rules.Wild] > 0 {
+		e.wild = rules.Wild
+	}
+	return e
+}
+
+// pick 按点数组合从手牌中取出对应的牌，缺少的牌用癞子代替
+// 癞子不够，或者全部由癞子代替别的点数时返回 false，后者只能当作癞子本身打出。
+func (e *enumerator) pick(ranks []card.Rank) ([]card.Card, bool) {
+	if e.wild != 0 && e.wildsNeeded(ranks) > e.counts[e.wild] {
+		return nil, false
+	}
+	used := make(map[card.Rank]int, len(ranks))
+	cards := make([]card.Card, 0, len(ranks))
+	substituted := 0
+	for _, r := range ranks {
+		if r != e.wild && used[r] < e.counts[r] {
+			cards = append(cards, e.byRank[r][used[r]])
+			used[r]++
+			continue
+		}
+		c := e.byRank[e.wild][used[e.wild]]
+		used[e.wild]++
+		c.As = r
+		if c.Substituted() {
+			substituted++
+		}
+		cards = append(cards, c)
+	}
+	return cards, substituted < len(cards)
+}
+
+// wildsNeeded 凑出 ranks 需要用掉的癞子张数，包括当作本身点数的癞子
+func (e *enumerator) wildsNeeded(ranks []card.Rank) int {
+	need := make(map[card.Rank]int, len(ranks))
+	for _, r := range ranks {
+		need[r]++
+	}
+	n := need[e.wild]
+	for r, k := range need {
+		if r != e.wild {
+			n += max(0, k-e.counts[r])
+		}
+	}
+	return n
+}
+
+// available 点数 r 最多能凑出的张数，癞子可以代替大小王以外的点数
+func (e *enumerator) available(r card.Rank) int {
+	if e.wild == 0 || r == e.wild || r > card.Rank2 {
+		return e.counts[r]
+	}
+	return e.counts[r] + e.counts[e.wild]
+}
+
+// withCount 返回数量不少于 n 的点数，exclude 中的点数除外
+func (e *enumerator) withCount(n int, exclude ...card.Rank) []card.Rank {
+	var ranks []card.Rank
+	for _, r := range e.ranks {
+		if e.counts[r] >= n && !slices.Contains(exclude, r) {
+			ranks = append(ranks, r)
+		}
+	}
+	return ranks
+}
+
+// withBody 返回算上癞子后能凑出不少于 n 张的点数，用于牌型的主体，exclude 中的点数除外
+func (e *enumerator) withBody(n int, exclude ...card.Rank) []card.Rank {
+	if e.wild == 0 {
+		return e.withCount(n, exclude...)
+	}
+	var ranks []card.Rank
+	for r := card.Rank3; r <= card.RankRedJoker; r++ {
+		if e.available(r) >= n && !slices.Contains(exclude, r) {
+			ranks = append(ranks, r)
+		}
+	}
+	return ranks
+}
+
+// allCombos 自由出牌时的全部候选（炸弹和王炸另行生成）
+func (e *enumerator) allCombos() [][]card.Rank {
+	var combos [][]card.Rank
+	for _, t := range []HandType{
+		Single, Pair, Trio, TrioWithSingle, TrioWithPair,
+		Straight, PairStraight, Plane, PlaneWithSingles, PlaneWithPairs,
+		FourWithTwo, FourWithTwoPairs,
+	} {
+		combos = append(combos, e.combosOfType(t, 0)...)
+	}
+	return combos
+}
+
+// combosOfType 生成指定牌型的候选，length 为 0 时不限制顺子、连对和飞机的长度
+func (e *enumerator) combosOfType(t HandType, length int) [][]card.Rank {
+	switch t {
+	case Single:
+		return e.sets(1)
+	case Pair:
+		return e.sets(2)
+	case Trio:
+		return e.trios(0)
+	case TrioWithSingle:
+		return e.trios(1)
+	case TrioWithPair:
+		return e.trios(2)
+	case Straight:
+		return e.chains(1, e.rules.MinStraightLen(), length, 0)
+	case PairStraight:
+		return e.chains(2, 3, length, 0)
+	case Plane:
+		return e.chains(3, 2, length, 0)
+	case PlaneWithSingles:
+		return e.chains(3, 2, length, 1)
+	case PlaneWithPairs:
+		return e.chains(3, 2, length, 2)
+	case FourWithTwo:
+		return e.fours(1)
+	case FourWithTwoPairs:
+		return e.fours(2)
+	default:
+		return nil
+	}
+}
+
+// sets 单张或对子
+func (e *enumerator) sets(n int) [][]card.Rank {
+	var combos [][]card.Rank
+	for _, r := range e.withBody(n) {
+		combos = append(combos, repeatRank(r, n))
+	}
+	return combos
+}
+
+// trios 三张，kicker: 0=不带, 1=带单, 2=带对
+func (e *enumerator) trios(kicker int) [][]card.Rank {
+	var combos [][]card.Rank
+	for _, r := range e.withBody(3) {
+		body := repeatRank(r, 3)
+		if kicker == 0 {
+			combos = append(combos, body)
+			continue
+		}
+		for _, k := range e.withCount(kicker, r) {
+			combos = append(combos, append(slices.Clone(body), repeatRank(k, kicker)...))
+		}
+	}
+	return combos
+}
+
+// chains 顺子、连对和飞机
+// width 为每个点数取的张数，minLen 为最短长度，length 非 0 时只取该长度，wing 为飞机每节所带的张数
+func (e *enumerator) chains(width, minLen, length, wing int) [][]card.Rank {
+	var combos [][]card.Rank
+	ranks := e.withBody(width)
+	for i := range ranks {
+		for j := i; j < len(ranks) && ranks[j] < card.Rank2 && ranks[j] == ranks[i]+card.Rank(j-i); j++ {
+			n := j - i + 1
+			if n < minLen || (length != 0 && n != length) {
+				continue
+			}
+			var body []card.Rank
+			for _, r := range ranks[i : j+1] {
+				body = append(body, repeatRank(r, width)...)
+			}
+			if e.wild != 0 && e.wildsNeeded(body) > e.counts[e.wild] {
+				continue
+			}
+			if wing == 0 {
+				combos = append(combos, body)
+				continue
+			}
+			for _, wings := range e.wings(wing, n, ranks[i:j+1]) {
+				combo := slices.Clone(body)
+				for _, w := range wings {
+					combo = append(combo, repeatRank(w, wing)...)
+				}
+				combos = append(combos, combo)
+			}
+		}
+	}
+	return combos
+}
+
+// wings 飞机的翅膀：从机身以外的牌中选出 n 组，每组 width 张
+// 带单时可以选同一个点数的多张牌，带对时同一个点数的四张可以拆成两对；机身多出来的第四张也可以作为单牌。
+func (e *enumerator) wings(width, n int, body []card.Rank) [][]card.Rank {
+	units := make(map[card.Rank]int)
+	for _, r := range e.ranks {
+		left := e.counts[r]
+		if slices.Contains(body, r) {
+			left -= 3
+		}
+		units[r] = left
+		if width == 2 {
+			units[r] = min(left/2, 2)
+		}
+	}
+	return multiCombinations(e.ranks, units, n)
+}
+
+// fours 四带二，kicker: 1=两张单牌或一对, 2=两对
+func (e *enumerator) fours(kicker int) [][]card.Rank {
+	var combos [][]card.Rank
+	for _, r := range e.withBody(4) {
+		body := repeatRank(r, 4)
+		for _, ks := range combinations(e.withCount(kicker, r), 2) {
+			combos = append(combos, append(slices.Clone(body), append(repeatRank(ks[0], kicker), repeatRank(ks[1], kicker)...)...))
+		}
+		if kicker == 2 {
+			for _, k := range e.withCount(4, r) { // 另一个炸弹拆成两对
+				combos = append(combos, append(slices.Clone(body), repeatRank(k, 4)...))
+			}
+		}
+		if kicker == 1 {
+			for _, k := range e.withCount(2, r) {
+				combos = append(combos, append(slices.Clone(body), k, k))
+			}
+		}
+	}
+	return combos
+}
+
+// bombs 炸弹，同一个点数有多于四张时每种张数都列出
+func (e *enumerator) bombs() [][]card.Rank {
+	var combos [][]card.Rank
+	for _, r := range e.withBody(4) {
+		for n := 4; n <= min(e.available(r), 4*e.rules.DeckCount()); n++ {
+			combos = append(combos, repeatRank(r, n))
+		}
+	}
+	return combos
+}
+
+// rocket 王炸
+func (e *enumerator) rocket() [][]card.Rank {
+	decks := e.rules.DeckCount()
+	if e.counts[card.RankBlackJoker] >= decks && e.counts[card.RankRedJoker] >= decks {
+		return [][]card.Rank{append(repeatRank(card.RankBlackJoker, decks), repeatRank(card.RankRedJoker, decks)...)}
+	}
+	return nil
+}
+
+// combinations 从 ranks 中选出 k 个不同点数的所有组合
+func combinations(ranks []card.Rank, k int) [][]card.Rank {
+	if k == 0 {
+		return [][]card.Rank{{}}
+	}
+	var result [][]card.Rank
+	for i := 0; i+k <= len(ranks); i++ {
+		for _, rest := range combinations(ranks[i+1:], k-1) {
+			result = append(result, append([]card.Rank{ranks[i]}, rest...))
+		}
+	}
+	return result
+}
+
+// multiCombinations 从 ranks 中选出 k 个点数的所有组合，每个点数最多选 units[r] 次
+func multiCombinations(ranks []card.Rank, units map[card.Rank]int, k int) [][]card.Rank {
+	if k == 0 {
+		return [][]card.Rank{{}}
+	}
+	var result [][]card.Rank
+	for i, r := range ranks {
+		for n := 1; n <= min(units[r], k); n++ {
+			for _, rest := range multiCombinations(ranks[i+1:], units, k-n) {
+				result = append(result, append(repeatRank(r, n), rest...))
+			}
+		}
+	}
+	return result
+}
+
+func repeatRank(r card.Rank, n int) []card.Rank {
+	ranks := make([]card.Rank, n)
+	for i := range ranks {
+		ranks[i] = r
+	}
+	return ranks
+}
+
+// rankKey 生成与顺序无关的点数组合标识，用于去重
+func rankKey(ranks []card.Rank) string {
+	sorted := slices.Clone(ranks)
+	slices.Sort(sorted)
+	var sb strings.Builder
+	for _, r := range sorted {
+		sb.WriteString(r.String())
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
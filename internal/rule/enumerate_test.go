@@ -0,0 +1,154 @@
+package rule
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playRanks 把出法转换成点数列表，便于断言
+func playRanks(plays []ParsedHand) [][]card.Rank {
+	result := make([][]card.Rank, len(plays))
+	for i, p := range plays {
+		for _, c := range p.Cards {
+			result[i] = append(result[i], c.Rank)
+		}
+	}
+	return result
+}
+
+// TestEnumerateLegalPlays verifies that every distinct legal play is listed.
+func TestEnumerateLegalPlays(t *testing.T) {
+	mustParse := func(ranks ...card.Rank) ParsedHand {
+		hands, err := ParseHand(Classic, testRuleCards(ranks...))
+		require.NoError(t, err)
+		return hands[0]
+	}
+
+	testCases := []struct {
+		name     string
+		hand     []card.Card
+		last     ParsedHand
+		expected [][]card.Rank
+	}{
+		{
+			name:     "free play lists singles and pairs",
+			hand:     testRuleCards(card.Rank3, card.Rank3, card.Rank4),
+			last:     ParsedHand{},
+			expected: [][]card.Rank{{card.Rank3}, {card.Rank4}, {card.Rank3, card.Rank3}},
+		},
+		{
+			name:     "only higher singles beat a single",
+			hand:     testRuleCards(card.Rank3, card.Rank4, card.Rank5),
+			last:     mustParse(card.Rank4),
+			expected: [][]card.Rank{{card.Rank5}},
+		},
+		{
+			name: "trio with single lists every kicker",
+			hand: testRuleCards(card.Rank5, card.Rank5, card.Rank5, card.Rank3, card.Rank4),
+			last: mustParse(card.Rank4, card.Rank4, card.Rank4, card.Rank9),
+			expected: [][]card.Rank{
+				{card.Rank5, card.Rank5, card.Rank5, card.Rank3},
+				{card.Rank5, card.Rank5, card.Rank5, card.Rank4},
+			},
+		},
+		{
+			name: "bombs and rocket beat a single two",
+			hand: testRuleCards(card.Rank7, card.Rank7, card.Rank7, card.Rank7, card.RankBlackJoker, card.RankRedJoker, card.Rank3),
+			last: mustParse(card.Rank2),
+			expected: [][]card.Rank{
+				{card.RankBlackJoker},
+				{card.RankRedJoker},
+				{card.Rank7, card.Rank7, card.Rank7, card.Rank7},
+				{card.RankBlackJoker, card.RankRedJoker},
+			},
+		},
+		{
+			name: "four with two keeps the bomb as an option",
+			hand: testRuleCards(card.Rank8, card.Rank8, card.Rank8, card.Rank8, card.Rank5, card.Rank6),
+			last: mustParse(card.Rank7, card.Rank7, card.Rank7, card.Rank7, card.Rank3, card.Rank4),
+			expected: [][]card.Rank{
+				{card.Rank8, card.Rank8, card.Rank8, card.Rank8},
+				{card.Rank8, card.Rank8, card.Rank8, card.Rank8, card.Rank5, card.Rank6},
+			},
+		},
+		{
+			name:     "nothing beats a rocket",
+			hand:     testRuleCards(card.Rank2, card.Rank2, card.Rank2, card.Rank2),
+			last:     mustParse(card.RankBlackJoker, card.RankRedJoker),
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			plays := EnumerateLegalPlays(Classic, tc.hand, tc.last)
+			assert.ElementsMatch(t, tc.expected, playRanks(plays))
+		})
+	}
+}
+
+// TestEnumerateLegalPlays_Kickers checks that plane and four-with-two kicker choices are all expanded.
+func TestEnumerateLegalPlays_Kickers(t *testing.T) {
+	t.Run("plane with singles", func(t *testing.T) {
+		hand := testRuleCards(card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4, card.Rank7, card.Rank8, card.Rank9)
+		last := ParsedHand{Type: PlaneWithSingles, KeyRank: card.Rank3 - 1, Length: 2}
+
+		plays := EnumerateLegalPlays(Classic, hand, last)
+		assert.Len(t, plays, 3, "C(3,2) wing choices")
+		for _, p := range plays {
+			assert.Equal(t, PlaneWithSingles, p.Type)
+			assert.Equal(t, card.Rank3, p.KeyRank)
+		}
+	})
+
+	t.Run("four with two pairs", func(t *testing.T) {
+		hand := testRuleCards(card.RankQ, card.RankQ, card.RankQ, card.RankQ, card.Rank5, card.Rank5, card.Rank6, card.Rank6, card.Rank7, card.Rank7)
+		last := ParsedHand{Type: FourWithTwoPairs, KeyRank: card.RankJ}
+
+		plays := EnumerateLegalPlays(Classic, hand, last)
+		var withPairs int
+		for _, p := range plays {
+			if p.Type == FourWithTwoPairs {
+				withPairs++
+			}
+		}
+		assert.Equal(t, 3, withPairs, "C(3,2) pair choices")
+	})
+}
+
+// TestEnumerateLegalPlays_Consistency makes sure every enumerated play parses back and beats the table.
+func TestEnumerateLegalPlays_Consistency(t *testing.T) {
+	hand := testRuleCards(
+		card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank5, card.Rank5, card.Rank5,
+		card.Rank6, card.Rank6, card.Rank6, card.Rank7, card.Rank8, card.Rank9, card.Rank10,
+		card.Rank2, card.Rank2, card.RankRedJoker,
+	)
+
+	free := EnumerateLegalPlays(Classic, hand, ParsedHand{})
+	require.NotEmpty(t, free)
+
+	seen := make(map[string]bool)
+	for _, p := range free {
+		var ranks []card.Rank
+		for _, c := range p.Cards {
+			ranks = append(ranks, c.Rank)
+		}
+		key := fmt.Sprintf("%s%d/%d/%d", rankKey(ranks), p.Type, p.KeyRank, p.Length)
+		assert.False(t, seen[key], "play %v listed twice", ranks)
+		seen[key] = true
+
+		parsed, err := ParseHand(Classic, p.Cards)
+		require.NoError(t, err)
+		assert.True(t, slices.ContainsFunc(parsed, p.SameAs), "%v", ranks)
+
+		for _, answer := range EnumerateLegalPlays(Classic, hand, p) {
+			assert.True(t, CanBeat(Classic, answer, p), "%v should beat %v", answer.Cards, p.Cards)
+		}
+	}
+}
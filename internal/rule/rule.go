@@ -1,6 +1,7 @@
 package rule
 
 import (
+	"cmp"
 	"fmt"
 	"slices"
 
@@ -29,13 +30,60 @@ const (
 	FourWithTwoPairs // 四带两对（带两对）
 
 	Rocket // 王炸（双王）
+
+	SoftBomb // 软炸（有癞子代替的炸弹）
+	WildBomb // 纯癞子炸弹（四张癞子）
 )
 
+var handTypeNames = map[HandType]string{
+	Single: "单张", Pair: "对子", Trio: "三张", TrioWithSingle: "三带一", TrioWithPair: "三带二",
+	Straight: "顺子", PairStraight: "连对", Plane: "飞机", PlaneWithSingles: "飞机带单", PlaneWithPairs: "飞机带对",
+	Bomb: "炸弹", FourWithTwo: "四带二", FourWithTwoPairs: "四带两对", Rocket: "王炸",
+	SoftBomb: "软炸", WildBomb: "纯癞子炸弹",
+}
+
+// String 返回牌型的中文名
+func (t HandType) String() string {
+	if name, ok := handTypeNames[t]; ok {
+		return name
+	}
+	return "无效牌型"
+}
+
+// IsBomb 是否是炸弹类的牌型：炸弹、软炸、纯癞子炸弹或王炸
+func (t HandType) IsBomb() bool {
+	return t.bombRank() > 0
+}
+
+// bombRank 炸弹之间的大小：软炸 < 炸弹 < 纯癞子炸弹 < 王炸，不是炸弹时为 0
+func (t HandType) bombRank() int {
+	switch t {
+	case SoftBomb:
+		return 1
+	case Bomb:
+		return 2
+	case WildBomb:
+		return 3
+	case Rocket:
+		return 4
+	}
+	return 0
+}
+
+// hasKickers 牌型是否带牌
+func (t HandType) hasKickers() bool {
+	switch t {
+	case TrioWithSingle, TrioWithPair, PlaneWithSingles, PlaneWithPairs, FourWithTwo, FourWithTwoPairs:
+		return true
+	}
+	return false
+}
+
 // ParsedHand 解析后的手牌，用于比较
 type ParsedHand struct {
 	Type    HandType
 	KeyRank card.Rank   // 决定大小的关键牌的点数 (例如 3334 中的 3, 或 34567 中的 3)
-	Length  int         // 牌型的长度，主要用于顺子、连对、飞机
+	Length  int         // 牌型的长度，主要用于顺子、连对、飞机；炸弹是它的张数
 	Cards   []card.Card // 这手牌包含的卡牌
 }
 
@@ -43,6 +91,44 @@ func (p ParsedHand) IsEmpty() bool {
 	return p.Type == Invalid
 }
 
+// Body 返回这手牌主体部分的点数，不含带牌，例如 444555666+333 的主体是 444555666
+func (p ParsedHand) Body() []card.Rank {
+	switch p.Type {
+	case TrioWithSingle, TrioWithPair:
+		return repeatRank(p.KeyRank, 3)
+	case FourWithTwo, FourWithTwoPairs:
+		return repeatRank(p.KeyRank, 4)
+	case PlaneWithSingles, PlaneWithPairs:
+		var body []card.Rank
+		for i := range p.Length {
+			body = append(body, repeatRank(p.KeyRank+card.Rank(i), 3)...)
+		}
+		return body
+	}
+	ranks := make([]card.Rank, len(p.Cards))
+	for i, c := range p.Cards {
+		ranks[i] = c.EffectiveRank()
+	}
+	return ranks
+}
+
+// bombSize 炸弹的张数，没有记录时按四张计算
+func (p ParsedHand) bombSize() int {
+	return max(p.Length, 4)
+}
+
+// SameAs 判断两手牌是否是同一种解释：牌型、关键牌和长度都相同
+// 炸弹按张数比较，没有记录张数的旧存档中的炸弹也能对上。
+func (p ParsedHand) SameAs(other ParsedHand) bool {
+	if p.Type != other.Type || p.KeyRank != other.KeyRank {
+		return false
+	}
+	if p.Type.IsBomb() {
+		return p.bombSize() == other.bombSize()
+	}
+	return p.Length == other.Length
+}
+
 // HandAnalysis 对一手牌进行预分析，统计不同点数的牌出现了几次
 type HandAnalysis struct {
 	counts map[card.Rank]int // 每种点数牌的数量
@@ -59,7 +145,7 @@ func analyzeCards(cards []card.Card) HandAnalysis {
 		counts: make(map[card.Rank]int),
 	}
 	for _, c := range cards {
-		analysis.counts[c.Rank]++
+		analysis.counts[c.EffectiveRank()]++
 	}
 
 	for r, count := range analysis.counts {
@@ -103,66 +189,142 @@ func isContinuous(ranks []card.Rank) bool {
 	return true
 }
 
-// ParseHand 解析牌型
-func ParseHand(cards []card.Card) (ParsedHand, error) {
+// ParseHand 按规则解析牌型，返回这手牌所有合法的解释
+// 同一手牌可能有多种解释，例如 333444555666 既是四连的飞机，也是 444555666 带 333 或 333444555 带 666 的飞机带单。
+// 不带牌的解释排在最前面，其次是四带二、三带和飞机带翅膀，同一牌型中关键牌大的在前；第一个是默认的解释。
+// 癞子玩法中没有指定 As 的癞子会尝试代替每一个点数，每种解释的 Cards 中记录了癞子代替的点数。
+func ParseHand(rules RuleSet, cards []card.Card) ([]ParsedHand, error) {
 	if len(cards) == 0 {
-		return ParsedHand{}, fmt.Errorf("不能出空牌")
+		return nil, fmt.Errorf("不能出空牌")
+	}
+
+	cards, free := rules.wildSlots(cards)
+	var hands []ParsedHand
+	for _, subs := range wildSubstitutions(rules.Wild, len(free)) {
+		for i, r := range subs {
+			cards[free[i]].As = r
+		}
+		for _, hand := range parseCards(rules, slices.Clone(cards)) {
+			// 同一种解释只保留用癞子代替得最少的那种
+			if !slices.ContainsFunc(hands, hand.SameAs) {
+				hands = append(hands, hand)
+			}
+		}
+	}
+	if len(hands) == 0 {
+		return nil, fmt.Errorf("不支持的牌型: %v", cards)
+	}
+
+	first := make(map[HandType]int) // 每种牌型第一次出现的位置，保持解析的顺序
+	for i, h := range hands {
+		if _, ok := first[h.Type]; !ok {
+			first[h.Type] = i
+		}
+	}
+	kickers := func(h ParsedHand) int {
+		if h.Type.hasKickers() {
+			return 1
+		}
+		return 0
 	}
+	slices.SortStableFunc(hands, func(a, b ParsedHand) int {
+		return cmp.Or(
+			cmp.Compare(kickers(a), kickers(b)),
+			cmp.Compare(first[a.Type], first[b.Type]),
+			cmp.Compare(b.KeyRank, a.KeyRank),
+		)
+	})
+	return hands, nil
+}
 
+// parseCards 按每张牌打出时的点数解析所有牌型
+func parseCards(rules RuleSet, cards []card.Card) []ParsedHand {
 	analysis := analyzeCards(cards)
+	var hands []ParsedHand
+	add := func(hand ParsedHand, ok bool) {
+		if ok {
+			hands = append(hands, hand)
+		}
+	}
 
 	// 王炸
-	if hand, ok := isRocket(analysis, cards); ok {
-		return hand, nil
-	}
+	add(isRocket(rules, analysis, cards))
 	// 炸弹
-	if hand, ok := isBomb(analysis, cards); ok {
-		return hand, nil
-	}
+	add(isBomb(rules, analysis, cards))
 	// 四带二
-	if hand, ok := isFourWithKickers(analysis, cards); ok {
-		return hand, nil
-	}
+	hands = append(hands, isFourWithKickers(rules, analysis, cards)...)
 	// 三带X
-	if hand, ok := isTrioWithKickers(analysis, cards); ok {
-		return hand, nil
-	}
+	add(isTrioWithKickers(analysis, cards))
 	// 飞机
-	if hand, ok := isPlane(analysis, cards); ok {
-		return hand, nil
-	}
+	hands = append(hands, isPlane(rules, analysis, cards)...)
 	// 顺子
-	if hand, ok := isStraight(analysis, cards); ok {
-		return hand, nil
-	}
+	add(isStraight(rules, analysis, cards))
 	// 连对
-	if hand, ok := isPairStraight(analysis, cards); ok {
-		return hand, nil
-	}
+	add(isPairStraight(analysis, cards))
 	// 简单牌型
-	if hand, ok := isSimpleType(analysis, cards); ok {
-		return hand, nil
-	}
+	add(isSimpleType(analysis, cards))
+	return hands
+}
 
-	return ParsedHand{}, fmt.Errorf("不支持的牌型: %v", cards)
+// wildSlots 整理 cards 中癞子代替的点数，返回整理后的牌和需要尝试代替的癞子的下标
+// 不是癞子的牌不能代替别的点数；全是癞子时只能当作本身的点数。
+func (rs RuleSet) wildSlots(cards []card.Card) ([]card.Card, []int) {
+	if !slices.ContainsFunc(cards, func(c card.Card) bool { return c.As != 0 || rs.IsWild(c) }) {
+		return cards, nil
+	}
+	cards = slices.Clone(cards)
+	allWild := !slices.ContainsFunc(cards, func(c card.Card) bool { return !rs.IsWild(c) })
+	var free []int
+	for i, c := range cards {
+		switch {
+		case !rs.IsWild(c):
+			cards[i].As = 0
+		case allWild:
+			cards[i].As = c.Rank
+		case c.As < card.Rank3 || c.As > card.Rank2:
+			free = append(free, i)
+		}
+	}
+	return cards, free
 }
 
-// CanBeat 判断 newHand 是否能大过 lastHand
-func CanBeat(newHand, lastHand ParsedHand) bool {
-	// 王炸最大
-	if newHand.Type == Rocket {
-		return true
+// wildSubstitutions 列出 n 张癞子可以代替的所有点数组合，代替别的点数越少的越靠前
+func wildSubstitutions(wild card.Rank, n int) [][]card.Rank {
+	ranks := make([]card.Rank, 0, card.Rank2-card.Rank3+1)
+	units := make(map[card.Rank]int)
+	for r := card.Rank3; r <= card.Rank2; r++ {
+		ranks = append(ranks, r)
+		units[r] = n
 	}
-	if lastHand.Type == Rocket {
-		return false
+	others := func(sub []card.Rank) int {
+		count := 0
+		for _, r := range sub {
+			if r != wild {
+				count++
+			}
+		}
+		return count
 	}
+	subs := multiCombinations(ranks, units, n)
+	slices.SortStableFunc(subs, func(a, b []card.Rank) int {
+		return cmp.Compare(others(a), others(b))
+	})
+	return subs
+}
 
-	// 炸弹可以大过任何非炸弹和非王炸的牌
-	if newHand.Type == Bomb && lastHand.Type != Bomb {
-		return true
+// CanBeat 判断 newHand 是否能大过 lastHand，两手牌都应该是按 rules 解析的
+// 目前的规则变体都只影响哪些牌型合法，不影响大小比较。
+func CanBeat(rules RuleSet, newHand, lastHand ParsedHand) bool {
+	// 炸弹大过任何不是炸弹的牌，炸弹之间先比较种类：软炸 < 炸弹 < 纯癞子炸弹 < 王炸
+	if nb, lb := newHand.Type.bombRank(), lastHand.Type.bombRank(); nb != lb {
+		return nb > lb
+	}
+	// 同一种炸弹张数多的大
+	if newHand.Type.IsBomb() && newHand.bombSize() != lastHand.bombSize() {
+		return newHand.bombSize() > lastHand.bombSize()
 	}
 
-	// 如果牌型不同 (且我不是炸弹)，不能出
+	// 如果牌型不同，不能出
 	if newHand.Type != lastHand.Type {
 		return false
 	}
@@ -172,23 +334,28 @@ func CanBeat(newHand, lastHand ParsedHand) bool {
 		return false
 	}
 
-	// 如果牌型相同或者是炸弹盖炸弹
+	// 如果牌型相同或者是同一种炸弹
 	return newHand.KeyRank > lastHand.KeyRank
 }
 
 var CanBeatWithHand = canBeatWithHand
 
-// CanBeatWithHand 检查一个玩家的整手牌中是否存在任何可以打过 opponentHand 的组合
-func canBeatWithHand(playerHand []card.Card, opponentHand ParsedHand) bool {
+// CanBeatWithHand 检查一个玩家的整手牌中是否存在任何按 rules 可以打过 opponentHand 的组合
+func canBeatWithHand(rules RuleSet, playerHand []card.Card, opponentHand ParsedHand) bool {
 	// 1. 如果是新一轮，总是有牌可出
 	if opponentHand.IsEmpty() {
 		return true
 	}
 
+	// 有癞子时能组成的牌型太多，直接列出所有出法
+	if slices.ContainsFunc(playerHand, rules.IsWild) {
+		return len(EnumerateLegalPlays(rules, playerHand, opponentHand)) > 0
+	}
+
 	analysis := analyzeCards(playerHand)
 
 	// 2. 检查是否有炸弹或王炸 (它们几乎可以打任何牌)
-	if hasWinningBombOrRocket(analysis, opponentHand) {
+	if hasWinningBombOrRocket(rules, analysis, opponentHand) {
 		return true
 	}
 
@@ -203,21 +370,16 @@ func canBeatWithHand(playerHand []card.Card, opponentHand ParsedHand) bool {
 	case Pair:
 		return findWinningPair(analysis, opponentHand)
 	case Trio:
-		return findWinningTrio(analysis, opponentHand, 0)
-	case TrioWithSingle:
-		return findWinningTrio(analysis, opponentHand, 1)
-	case TrioWithPair:
-		return findWinningTrio(analysis, opponentHand, 2)
+		return findWinningTrio(analysis, opponentHand)
 	case Straight:
 		return findWinningStraight(analysis, opponentHand)
 	case PairStraight:
 		return findWinningPairStraight(analysis, opponentHand)
 	case Plane:
-		return findWinningPlane(analysis, opponentHand, 0)
-	case PlaneWithSingles:
-		return findWinningPlane(analysis, opponentHand, 1)
-	case PlaneWithPairs:
-		return findWinningPlane(analysis, opponentHand, 2)
+		return findWinningPlane(analysis, opponentHand)
+	case TrioWithSingle, TrioWithPair, PlaneWithSingles, PlaneWithPairs, FourWithTwo, FourWithTwoPairs:
+		// 带牌是否合法取决于规则，直接列出所有出法
+		return len(EnumerateLegalPlays(rules, playerHand, opponentHand)) > 0
 	default:
 		return false
 	}
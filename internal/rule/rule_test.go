@@ -1,11 +1,13 @@
 package rule
 
 import (
+	"slices"
 	"sort"
 	"testing"
 
 	"github.com/palemoky/fight-the-landlord-go/internal/card"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // testRuleCards is a helper to quickly create card slices for testing.
@@ -105,12 +107,14 @@ func TestParseHand(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			parsedHand, err := ParseHand(tc.cards)
+			hands, err := ParseHand(Classic, tc.cards)
 
 			if tc.expectError {
 				assert.Error(t, err)
 			} else {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				require.Len(t, hands, 1)
+				parsedHand := hands[0]
 				assert.Equal(t, tc.expectedType, parsedHand.Type, "Hand type should match")
 				assert.Equal(t, tc.expectedRank, parsedHand.KeyRank, "Key rank should match")
 				if tc.expectedLen > 0 {
@@ -121,6 +125,66 @@ func TestParseHand(t *testing.T) {
 	}
 }
 
+// TestParseHand_Interpretations checks hands that can be read in more than one way.
+func TestParseHand_Interpretations(t *testing.T) {
+	type reading struct {
+		Type    HandType
+		KeyRank card.Rank
+		Length  int
+	}
+	r := card.Rank3
+	fourTrios := testRuleCards(r, r, r, r+1, r+1, r+1, r+2, r+2, r+2, r+3, r+3, r+3)
+	bombWings := append(slices.Clone(fourTrios), testRuleCards(card.Rank7, card.Rank7, card.Rank7, card.Rank7)...)
+
+	testCases := []struct {
+		name     string
+		rules    RuleSet
+		cards    []card.Card
+		expected []reading // 为空表示不合法
+	}{
+		{"four trios", Classic, fourTrios, []reading{{Plane, r, 4}, {PlaneWithSingles, r + 1, 3}, {PlaneWithSingles, r, 3}}},
+		{"wings with a pair", Classic, testRuleCards(r, r, r, r+1, r+1, r+1, r+2, r+2), []reading{{PlaneWithSingles, r, 2}}},
+		{"two bombs", Mobile, testRuleCards(card.RankJ, card.RankJ, card.RankJ, card.RankJ, card.RankQ, card.RankQ, card.RankQ, card.RankQ),
+			[]reading{{FourWithTwoPairs, card.RankQ, 0}, {FourWithTwoPairs, card.RankJ, 0}, {PlaneWithSingles, card.RankJ, 2}}},
+		{"bomb split into wings", Mobile, bombWings, []reading{{PlaneWithSingles, r + 1, 4}, {PlaneWithSingles, r, 4}}},
+		{"classic bomb wings", Classic, bombWings, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			hands, err := ParseHand(tc.rules, tc.cards)
+			if tc.expected == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var got []reading
+			for _, h := range hands {
+				got = append(got, reading{h.Type, h.KeyRank, h.Length})
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestParsedHand_Body(t *testing.T) {
+	hands, err := ParseHand(Classic, testRuleCards(card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4, card.Rank5, card.Rank5, card.Rank5, card.Rank6, card.Rank6, card.Rank6))
+	require.NoError(t, err)
+	require.Len(t, hands, 3)
+	assert.Len(t, hands[0].Body(), 12)
+	assert.Equal(t, []card.Rank{card.Rank4, card.Rank4, card.Rank4, card.Rank5, card.Rank5, card.Rank5, card.Rank6, card.Rank6, card.Rank6}, hands[1].Body())
+	assert.Equal(t, "飞机带单", hands[1].Type.String())
+}
+
+// TestParsedHand_SameAs checks that bombs without a recorded size compare as four cards.
+func TestParsedHand_SameAs(t *testing.T) {
+	bomb := ParsedHand{Type: Bomb, KeyRank: card.Rank7, Length: 4}
+	assert.True(t, bomb.SameAs(ParsedHand{Type: Bomb, KeyRank: card.Rank7}))
+	assert.False(t, bomb.SameAs(ParsedHand{Type: Bomb, KeyRank: card.Rank7, Length: 5}))
+	assert.False(t, ParsedHand{Type: Straight, KeyRank: card.Rank3, Length: 5}.SameAs(ParsedHand{Type: Straight, KeyRank: card.Rank3, Length: 6}))
+}
+
 func TestCanBeat(t *testing.T) {
 	// Helper to quickly create a parsed hand for testing
 	ph := func(ht HandType, kr card.Rank, l int) ParsedHand {
@@ -156,7 +220,7 @@ func TestCanBeat(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			assert.Equal(t, tc.expected, CanBeat(tc.newHand, tc.lastHand))
+			assert.Equal(t, tc.expected, CanBeat(Classic, tc.newHand, tc.lastHand))
 		})
 	}
 }
@@ -355,7 +419,7 @@ func TestCanBeatWithHand(t *testing.T) {
 			// Sort the hand as it would be in the game, which might affect some logic.
 			sort.Slice(tc.playerHand, func(i, j int) bool { return tc.playerHand[i].Rank < tc.playerHand[j].Rank })
 
-			actual := CanBeatWithHand(tc.playerHand, tc.opponentHand)
+			actual := CanBeatWithHand(Classic, tc.playerHand, tc.opponentHand)
 			assert.Equal(t, tc.expected, actual)
 		})
 	}
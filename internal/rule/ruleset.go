@@ -0,0 +1,136 @@
+package rule
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+)
+
+// defaultMinStraight 顺子默认的最短长度
+const defaultMinStraight = 5
+
+// 默认的人数、牌数和底牌张数
+const (
+	defaultPlayers     = 3
+	defaultDecks       = 1
+	defaultBottomCards = 3
+)
+
+// RuleSet 一桌约定的规则变体，零值等同于 Classic
+type RuleSet struct {
+	Name string // 规则的名字，记录在棋谱中；为空时视为 classic
+
+	MinStraight           int  // 顺子的最短长度，0 表示 5 张
+	FourWithTwoNoPair     bool // 四带二只能带两张不同点数的单牌，不能带一对
+	NoFourWithTwoPairs    bool // 不允许四带两对
+	NoRocketKickers       bool // 大小王不能同时作为带牌
+	BombKickers           bool // 四张相同的牌可以拆成两对作为带牌，例如 JJJJ+QQQQ
+	PlaneKickersShareRank bool // 飞机带单时翅膀可以和飞机中的三张同点数，例如 333444+3+5
+	WildCards             bool // 癞子玩法：发牌后翻出一个点数作为癞子
+	Players               int  // 玩家人数，0 表示 3 人
+	Decks                 int  // 使用几副牌，0 表示 1 副；多副牌时王炸要所有的王
+	BottomCards           int  // 底牌张数，0 表示 3 张
+	HandCards             int  // 每人发几张牌，0 表示底牌以外的牌平分；发剩下的牌谁也看不到
+
+	LowestRank card.Rank // 去掉比它小的点数，例如 Rank5 表示去掉 3 和 4；0 表示不去牌
+
+	Wild card.Rank // 本局的癞子点数，发牌时翻出，0 表示没有癞子
+}
+
+// 预设的规则
+var (
+	// Classic 经典规则：四带二可以带一对，可以四带两对，大小王可以一起作为带牌
+	Classic = RuleSet{Name: "classic"}
+	// Mobile 常见手机斗地主的规则：大小王不能作为带牌，炸弹可以拆成两对带出，飞机的翅膀可以和三张同点数
+	Mobile = RuleSet{Name: "mobile", NoRocketKickers: true, BombKickers: true, PlaneKickersShareRank: true}
+	// Tournament 比赛规则：四带二只能带两张不同的单牌，大小王不能作为带牌
+	Tournament = RuleSet{Name: "tournament", FourWithTwoNoPair: true, NoRocketKickers: true}
+	// Laizi 癞子规则：在经典规则的基础上，癞子可以代替大小王以外的任何点数
+	Laizi = RuleSet{Name: "laizi", WildCards: true}
+	// FourPlayer 四人斗地主：两副牌，每人 25 张，底牌 8 张；炸弹张数多的大，四张王是最大的王炸
+	FourPlayer = RuleSet{Name: "four", Players: 4, Decks: 2, BottomCards: 8}
+	// TwoPlayer 两人斗地主：去掉 3 和 4，每人 17 张，底牌 3 张，剩下的 9 张不用，双方都看不到
+	TwoPlayer = RuleSet{Name: "two", Players: 2, HandCards: 17, LowestRank: card.Rank5}
+)
+
+// Presets 所有预设的规则
+var Presets = []RuleSet{Classic, Mobile, Tournament, Laizi, FourPlayer, TwoPlayer}
+
+// ParseRuleSet 按名字查找预设的规则
+func ParseRuleSet(name string) (RuleSet, error) {
+	for _, rs := range Presets {
+		if rs.Name == name {
+			return rs, nil
+		}
+	}
+	return RuleSet{}, fmt.Errorf("未知的规则: %s", name)
+}
+
+// String 返回规则的名字
+func (rs RuleSet) String() string {
+	if rs.Name == "" {
+		return Classic.Name
+	}
+	return rs.Name
+}
+
+// MinStraightLen 顺子的最短长度
+func (rs RuleSet) MinStraightLen() int {
+	if rs.MinStraight <= 0 {
+		return defaultMinStraight
+	}
+	return rs.MinStraight
+}
+
+// PlayerCount 玩家人数
+func (rs RuleSet) PlayerCount() int {
+	if rs.Players <= 0 {
+		return defaultPlayers
+	}
+	return rs.Players
+}
+
+// DeckCount 使用几副牌
+func (rs RuleSet) DeckCount() int {
+	if rs.Decks <= 0 {
+		return defaultDecks
+	}
+	return rs.Decks
+}
+
+// BottomCount 底牌张数
+func (rs RuleSet) BottomCount() int {
+	if rs.BottomCards <= 0 {
+		return defaultBottomCards
+	}
+	return rs.BottomCards
+}
+
+// HandSize 发牌时每人的张数，不含底牌
+func (rs RuleSet) HandSize() int {
+	if rs.HandCards > 0 {
+		return rs.HandCards
+	}
+	return (rs.DeckSize() - rs.BottomCount()) / rs.PlayerCount()
+}
+
+// DeckSize 去掉不用的点数之后一共有多少张牌
+func (rs RuleSet) DeckSize() int {
+	removed := max(int(rs.LowestRank-card.Rank3), 0)
+	return (54 - 4*removed) * rs.DeckCount()
+}
+
+// NewDeck 按规则准备一局要用的牌：DeckCount 副牌，去掉比 LowestRank 小的点数
+func (rs RuleSet) NewDeck() card.Deck {
+	deck := card.NewDecks(rs.DeckCount())
+	if rs.LowestRank == 0 {
+		return deck
+	}
+	return slices.DeleteFunc(deck, func(c card.Card) bool { return c.Rank < rs.LowestRank })
+}
+
+// IsWild 判断 c 是否是本局的癞子
+func (rs RuleSet) IsWild(c card.Card) bool {
+	return rs.Wild != 0 && c.Rank == rs.Wild
+}
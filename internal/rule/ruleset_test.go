@@ -0,0 +1,336 @@
+package rule
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseHand_RuleSets checks the hands whose legality depends on the rule set.
+func TestParseHand_RuleSets(t *testing.T) {
+	fourWithPair := testRuleCards(card.Rank4, card.Rank4, card.Rank4, card.Rank4, card.Rank5, card.Rank5)
+	fourWithRocket := testRuleCards(card.Rank4, card.Rank4, card.Rank4, card.Rank4, card.RankBlackJoker, card.RankRedJoker)
+	fourWithTwoPairs := testRuleCards(card.RankJ, card.RankJ, card.RankJ, card.RankJ, card.RankQ, card.RankQ, card.RankK, card.RankK)
+	twoBombs := testRuleCards(card.RankJ, card.RankJ, card.RankJ, card.RankJ, card.RankQ, card.RankQ, card.RankQ, card.RankQ)
+	planeSharingRank := testRuleCards(card.Rank3, card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4, card.Rank5)
+	planeWithRocket := testRuleCards(card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4, card.RankBlackJoker, card.RankRedJoker)
+	planeWithBomb := testRuleCards(card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4, card.Rank9, card.Rank9, card.Rank9, card.Rank9)
+	shortStraight := testRuleCards(card.Rank3, card.Rank4, card.Rank5, card.Rank6)
+
+	testCases := []struct {
+		name     string
+		rules    RuleSet
+		cards    []card.Card
+		expected HandType // Invalid 表示不合法
+		keyRank  card.Rank
+	}{
+		{"classic four with a pair", Classic, fourWithPair, FourWithTwo, card.Rank4},
+		{"tournament four with a pair", Tournament, fourWithPair, Invalid, 0},
+		{"classic four with the rocket", Classic, fourWithRocket, FourWithTwo, card.Rank4},
+		{"mobile four with the rocket", Mobile, fourWithRocket, Invalid, 0},
+		{"classic four with two pairs", Classic, fourWithTwoPairs, FourWithTwoPairs, card.RankJ},
+		{"no four with two pairs", RuleSet{NoFourWithTwoPairs: true}, fourWithTwoPairs, Invalid, 0},
+		{"classic bomb as kickers", Classic, twoBombs, Invalid, 0},
+		{"mobile bomb as kickers", Mobile, twoBombs, FourWithTwoPairs, card.RankQ},
+		{"classic plane kicker sharing a rank", Classic, planeSharingRank, Invalid, 0},
+		{"mobile plane kicker sharing a rank", Mobile, planeSharingRank, PlaneWithSingles, card.Rank3},
+		{"classic plane with the rocket", Classic, planeWithRocket, PlaneWithSingles, card.Rank3},
+		{"tournament plane with the rocket", Tournament, planeWithRocket, Invalid, 0},
+		{"classic plane with a bomb as pairs", Classic, planeWithBomb, Invalid, 0},
+		{"mobile plane with a bomb as pairs", Mobile, planeWithBomb, PlaneWithPairs, card.Rank3},
+		{"four card straight is too short", Classic, shortStraight, Invalid, 0},
+		{"four card straight allowed", RuleSet{MinStraight: 4}, shortStraight, Straight, card.Rank3},
+		{"zero value is classic", RuleSet{}, fourWithPair, FourWithTwo, card.Rank4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			hands, err := ParseHand(tc.rules, tc.cards)
+			if tc.expected == Invalid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, hands[0].Type)
+			assert.Equal(t, tc.keyRank, hands[0].KeyRank)
+		})
+	}
+}
+
+// TestEnumerateLegalPlays_RuleSets makes sure the enumeration follows the same rules as ParseHand.
+func TestEnumerateLegalPlays_RuleSets(t *testing.T) {
+	hand := testRuleCards(
+		card.Rank3, card.Rank3, card.Rank3, card.Rank3,
+		card.Rank4, card.Rank4, card.Rank4, card.Rank5, card.Rank5,
+		card.Rank9, card.Rank9, card.Rank9, card.Rank9,
+		card.RankBlackJoker, card.RankRedJoker,
+	)
+	for _, rules := range Presets {
+		t.Run(rules.String(), func(t *testing.T) {
+			t.Parallel()
+			types := make(map[HandType]int)
+			for _, p := range EnumerateLegalPlays(rules, hand, ParsedHand{}) {
+				parsed, err := ParseHand(rules, p.Cards)
+				require.NoError(t, err, "%v", p.Cards)
+				assert.True(t, slices.ContainsFunc(parsed, p.SameAs), "%v", p.Cards)
+				types[p.Type]++
+			}
+			assert.Positive(t, types[FourWithTwo])
+			assert.Positive(t, types[PlaneWithSingles])
+		})
+	}
+
+	// 333+4444 作为翅膀只有 mobile 规则允许
+	lasts, err := ParseHand(Mobile, testRuleCards(card.Rank3, card.Rank3, card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank4, card.Rank5))
+	require.NoError(t, err)
+	last := lasts[0]
+	follow := testRuleCards(card.Rank6, card.Rank6, card.Rank6, card.Rank6, card.Rank7, card.Rank7, card.Rank7, card.Rank3)
+	assert.NotEmpty(t, EnumerateLegalPlays(Mobile, follow, last))
+	assert.True(t, CanBeatWithHand(Mobile, follow, last))
+	assert.False(t, CanBeatWithHand(Classic, testRuleCards(card.Rank6, card.Rank6, card.Rank6, card.Rank7, card.Rank7, card.Rank7, card.Rank8), last))
+}
+
+// TestCanBeatWithHand_FourWithTwo covers following a four with two, which needs the kicker rules.
+func TestCanBeatWithHand_FourWithTwo(t *testing.T) {
+	lasts, err := ParseHand(Classic, testRuleCards(card.Rank4, card.Rank4, card.Rank4, card.Rank4, card.Rank5, card.Rank6))
+	require.NoError(t, err)
+	last := lasts[0]
+
+	withPair := testRuleCards(card.Rank8, card.Rank8, card.Rank8, card.Rank8, card.Rank3, card.Rank3)
+	assert.True(t, CanBeatWithHand(Classic, withPair, last), "a bomb always beats it")
+
+	// 不算炸弹，只能用更大的四带二
+	assert.NotEmpty(t, filterType(EnumerateLegalPlays(Classic, withPair, last), FourWithTwo))
+	assert.Empty(t, filterType(EnumerateLegalPlays(Tournament, withPair, last), FourWithTwo), "tournament rules do not allow a pair as the two kickers")
+}
+
+func filterType(plays []ParsedHand, t HandType) []ParsedHand {
+	var result []ParsedHand
+	for _, p := range plays {
+		if p.Type == t {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func TestParseRuleSet(t *testing.T) {
+	for _, rs := range Presets {
+		parsed, err := ParseRuleSet(rs.String())
+		require.NoError(t, err)
+		assert.Equal(t, rs, parsed)
+	}
+	_, err := ParseRuleSet("sichuan")
+	assert.Error(t, err)
+	assert.Equal(t, "classic", RuleSet{}.String())
+}
+
+// TestParseHand_Wildcards checks how wildcards stand in for other ranks.
+func TestParseHand_Wildcards(t *testing.T) {
+	rules := Laizi
+	rules.Wild = card.Rank9
+	wildAs := func(r card.Rank) card.Card { return card.Card{Rank: card.Rank9, As: r} }
+
+	testCases := []struct {
+		name     string
+		rules    RuleSet
+		cards    []card.Card
+		expected []ParsedHand // 按顺序的所有解释，只比较牌型、关键牌和长度
+		wildAs   []card.Rank  // 每种解释中第一张癞子代替的点数
+	}{
+		{
+			name:     "wildcard completes a pair",
+			rules:    rules,
+			cards:    testRuleCards(card.Rank5, card.Rank9),
+			expected: []ParsedHand{{Type: Pair, KeyRank: card.Rank5}},
+			wildAs:   []card.Rank{card.Rank5},
+		},
+		{
+			name:     "wildcards alone keep their own rank",
+			rules:    rules,
+			cards:    testRuleCards(card.Rank9, card.Rank9),
+			expected: []ParsedHand{{Type: Pair, KeyRank: card.Rank9}},
+			wildAs:   []card.Rank{card.Rank9},
+		},
+		{
+			name:  "three of a kind and a wildcard",
+			rules: rules,
+			cards: testRuleCards(card.Rank5, card.Rank5, card.Rank5, card.Rank9),
+			expected: []ParsedHand{
+				{Type: SoftBomb, KeyRank: card.Rank5, Length: 4},
+				{Type: TrioWithSingle, KeyRank: card.Rank5},
+			},
+			wildAs: []card.Rank{card.Rank5, card.Rank9},
+		},
+		{
+			name:     "four wildcards",
+			rules:    rules,
+			cards:    testRuleCards(card.Rank9, card.Rank9, card.Rank9, card.Rank9),
+			expected: []ParsedHand{{Type: WildBomb, KeyRank: card.Rank9, Length: 4}},
+			wildAs:   []card.Rank{card.Rank9},
+		},
+		{
+			name:  "wildcard at either end of a straight",
+			rules: rules,
+			cards: testRuleCards(card.Rank4, card.Rank5, card.Rank6, card.Rank7, card.Rank9),
+			expected: []ParsedHand{
+				{Type: Straight, KeyRank: card.Rank4, Length: 5},
+				{Type: Straight, KeyRank: card.Rank3, Length: 5},
+			},
+			wildAs: []card.Rank{card.Rank8, card.Rank3},
+		},
+		{
+			name:     "substitution given in advance",
+			rules:    rules,
+			cards:    append(testRuleCards(card.Rank4, card.Rank5, card.Rank6, card.Rank7), wildAs(card.Rank3)),
+			expected: []ParsedHand{{Type: Straight, KeyRank: card.Rank3, Length: 5}},
+			wildAs:   []card.Rank{card.Rank3},
+		},
+		{
+			name:     "no wildcards in classic rules",
+			rules:    Classic,
+			cards:    append(testRuleCards(card.Rank5), wildAs(card.Rank5)),
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			hands, err := ParseHand(tc.rules, tc.cards)
+			if tc.expected == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, hands, len(tc.expected))
+			for i, h := range hands {
+				assert.True(t, tc.expected[i].SameAs(h), "got %v %v", h.Type, h.KeyRank)
+				w := slices.IndexFunc(h.Cards, tc.rules.IsWild)
+				require.GreaterOrEqual(t, w, 0)
+				assert.Equal(t, tc.wildAs[i], h.Cards[w].As, "the substitution is reported in Cards")
+			}
+		})
+	}
+}
+
+// TestCanBeat_BombKinds checks the order soft bomb < bomb < wildcard bomb < rocket.
+func TestCanBeat_BombKinds(t *testing.T) {
+	kinds := []ParsedHand{
+		{Type: SoftBomb, KeyRank: card.Rank5},
+		{Type: SoftBomb, KeyRank: card.RankA},
+		{Type: Bomb, KeyRank: card.Rank3},
+		{Type: Bomb, KeyRank: card.Rank2},
+		{Type: WildBomb, KeyRank: card.Rank9},
+		{Type: Rocket, KeyRank: card.RankRedJoker},
+	}
+	for i, weaker := range kinds {
+		for _, stronger := range kinds[i+1:] {
+			assert.True(t, CanBeat(Laizi, stronger, weaker), "%v %v beats %v %v", stronger.Type, stronger.KeyRank, weaker.Type, weaker.KeyRank)
+			assert.False(t, CanBeat(Laizi, weaker, stronger), "%v %v beats %v %v", weaker.Type, weaker.KeyRank, stronger.Type, stronger.KeyRank)
+		}
+		assert.True(t, CanBeat(Laizi, weaker, ParsedHand{Type: Pair, KeyRank: card.Rank2}))
+	}
+}
+
+// TestEnumerateLegalPlays_Wildcards checks that the enumeration uses wildcards to fill in hands.
+func TestEnumerateLegalPlays_Wildcards(t *testing.T) {
+	rules := Laizi
+	rules.Wild = card.Rank9
+	hand := testRuleCards(card.Rank5, card.Rank5, card.Rank5, card.Rank9, card.RankK)
+	last := ParsedHand{Type: Pair, KeyRank: card.Rank6}
+
+	plays := EnumerateLegalPlays(rules, hand, last)
+	assert.True(t, slices.ContainsFunc(plays, ParsedHand{Type: Pair, KeyRank: card.RankK}.SameAs), "K and a wildcard")
+	assert.True(t, slices.ContainsFunc(plays, ParsedHand{Type: SoftBomb, KeyRank: card.Rank5, Length: 4}.SameAs))
+	assert.False(t, slices.ContainsFunc(plays, ParsedHand{Type: Pair, KeyRank: card.Rank9}.SameAs), "a single wildcard is not a pair")
+	assert.False(t, CanBeatWithHand(rules, hand, ParsedHand{Type: Bomb, KeyRank: card.Rank3}), "a soft bomb is smaller than any bomb")
+	assert.True(t, CanBeatWithHand(rules, hand, last))
+
+	for _, p := range EnumerateLegalPlays(rules, hand, ParsedHand{}) {
+		parsed, err := ParseHand(rules, p.Cards)
+		require.NoError(t, err, "%v", p.Cards)
+		assert.True(t, slices.ContainsFunc(parsed, p.SameAs), "%v", p.Cards)
+	}
+}
+
+// TestRuleSet_NewDeck checks the deck each preset deals from.
+func TestRuleSet_NewDeck(t *testing.T) {
+	testCases := []struct {
+		rules RuleSet
+		size  int
+		hand  int
+	}{
+		{rules: Classic, size: 54, hand: 17},
+		{rules: FourPlayer, size: 108, hand: 25},
+		{rules: TwoPlayer, size: 46, hand: 17},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rules.Name, func(t *testing.T) {
+			t.Parallel()
+			deck := tc.rules.NewDeck()
+			assert.Len(t, deck, tc.size)
+			assert.Equal(t, tc.size, tc.rules.DeckSize())
+			assert.Equal(t, tc.hand, tc.rules.HandSize())
+			assert.LessOrEqual(t, tc.hand*tc.rules.PlayerCount()+tc.rules.BottomCount(), tc.size)
+			for _, c := range deck {
+				assert.GreaterOrEqual(t, c.Rank, tc.rules.LowestRank)
+			}
+		})
+	}
+}
+
+// TestFourPlayer checks the deal sizes and that bombs rank by size with four jokers on top.
+func TestFourPlayer(t *testing.T) {
+	t.Parallel()
+	rules := FourPlayer
+	eight := repeatCards(card.Rank3, 8)
+	hands, err := ParseHand(rules, eight)
+	require.NoError(t, err)
+	assert.True(t, ParsedHand{Type: Bomb, KeyRank: card.Rank3, Length: 8}.SameAs(hands[0]))
+	_, err = ParseHand(Classic, repeatCards(card.Rank3, 5))
+	assert.Error(t, err, "one deck has no five of a kind")
+
+	kings := testRuleCards(card.RankBlackJoker, card.RankBlackJoker, card.RankRedJoker, card.RankRedJoker)
+	hands, err = ParseHand(rules, kings)
+	require.NoError(t, err)
+	assert.Equal(t, Rocket, hands[0].Type)
+	_, err = ParseHand(rules, testRuleCards(card.RankBlackJoker, card.RankRedJoker))
+	assert.Error(t, err, "two jokers are not a rocket with two decks")
+
+	sizes := []ParsedHand{
+		{Type: Bomb, KeyRank: card.Rank2, Length: 4},
+		{Type: Bomb, KeyRank: card.Rank3, Length: 5},
+		{Type: Bomb, KeyRank: card.Rank4, Length: 5},
+		{Type: Bomb, KeyRank: card.Rank3, Length: 8},
+		hands[0],
+	}
+	for i, weaker := range sizes {
+		for _, stronger := range sizes[i+1:] {
+			assert.True(t, CanBeat(rules, stronger, weaker), "%v beats %v", stronger.Length, weaker.Length)
+			assert.False(t, CanBeat(rules, weaker, stronger))
+		}
+	}
+
+	hand := append(repeatCards(card.Rank5, 6), kings...)
+	plays := EnumerateLegalPlays(rules, hand, ParsedHand{Type: Bomb, KeyRank: card.Rank9, Length: 5})
+	assert.True(t, slices.ContainsFunc(plays, ParsedHand{Type: Bomb, KeyRank: card.Rank5, Length: 6}.SameAs))
+	assert.False(t, slices.ContainsFunc(plays, ParsedHand{Type: Bomb, KeyRank: card.Rank5, Length: 5}.SameAs))
+	assert.True(t, slices.ContainsFunc(plays, func(p ParsedHand) bool { return p.Type == Rocket }))
+	assert.False(t, CanBeatWithHand(rules, repeatCards(card.Rank5, 5), ParsedHand{Type: Bomb, KeyRank: card.Rank3, Length: 6}))
+	assert.True(t, CanBeatWithHand(rules, repeatCards(card.Rank5, 6), ParsedHand{Type: Bomb, KeyRank: card.Rank3, Length: 6}))
+}
+
+// repeatCards n cards of rank r, as when several decks are in play
+func repeatCards(r card.Rank, n int) []card.Card {
+	ranks := make([]card.Rank, n)
+	for i := range ranks {
+		ranks[i] = r
+	}
+	return testRuleCards(ranks...)
+}
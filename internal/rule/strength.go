@@ -0,0 +1,90 @@
+package rule
+
+import (
+	"math"
+	"slices"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+)
+
+// Strength 一手牌当地主的实力估计
+type Strength struct {
+	Jokers int     // 王的张数
+	Twos   int     // 2 的张数
+	Wilds  int     // 癞子的张数
+	Bombs  int     // 炸弹的个数，王炸也算一个
+	Plays  int     // 最少要出几手才能出完，见 Decompose
+	Score  float64 // 0 到 1 之间的牌力评分，越高越适合当地主；权重是手工取的，不是胜率
+}
+
+// Controls 控制牌的张数：王、2 和癞子，炸弹另算
+func (s Strength) Controls() int {
+	return s.Jokers + s.Twos + s.Wilds
+}
+
+// BidLevel 按牌力评分建议叫几分，0 表示不叫
+func (s Strength) BidLevel() int {
+	switch {
+	case s.Score >= 0.6:
+		return 3
+	case s.Score >= 0.5:
+		return 2
+	case s.Score >= 0.4:
+		return 1
+	}
+	return 0
+}
+
+// strengthWeights 牌力评分的对数几率系数，手工取值，没有用对局结果校准过
+// 控制牌越大越值钱：大王 > 小王 > 2 > A；炸弹在四张牌之外再加一点；每多一手牌就要多抢一次牌权。
+type strengthWeights struct {
+	bias, redJoker, blackJoker, two, ace, bomb, plays float64
+}
+
+var (
+	// bidWeights 叫地主时的手牌，还没拿到底牌
+	// 偏置取使随机发的手牌中大约三分之一建议叫地主，十分之一建议叫 3 分。
+	bidWeights = strengthWeights{bias: -0.6, redJoker: 1.2, blackJoker: 0.8, two: 0.4, ace: 0.2, bomb: 0.3, plays: -0.2}
+	// landlordWeights 拿到底牌之后的手牌，底牌已经计入手牌，偏置减去三张随机底牌的平均贡献
+	landlordWeights = strengthWeights{bias: -0.88, redJoker: 1.2, blackJoker: 0.8, two: 0.4, ace: 0.2, bomb: 0.3, plays: -0.2}
+)
+
+// Evaluate 估计 hand 当地主的实力
+// 手牌张数不超过 rules.HandSize() 时视为还没拿底牌的叫地主手牌，评分已经考虑了底牌的平均贡献；
+// 否则视为拿到底牌之后的手牌。癞子按一张 2 计算。
+func Evaluate(rules RuleSet, hand []card.Card) Strength {
+	counts := card.CountRanks(hand)
+	s := Strength{
+		Jokers: counts[card.RankBlackJoker] + counts[card.RankRedJoker],
+		Twos:   counts[card.Rank2],
+	}
+	if rules.Wild != 0 {
+		s.Wilds, counts[rules.Wild] = counts[rules.Wild], 0
+		if rules.Wild == card.Rank2 {
+			s.Twos = 0
+		}
+	}
+	for r := card.Rank3; r <= card.Rank2; r++ {
+		if counts[r] >= 4 {
+			s.Bombs++
+		}
+	}
+	if decks := rules.DeckCount(); counts[card.RankBlackJoker] >= decks && counts[card.RankRedJoker] >= decks {
+		s.Bombs++
+	}
+	s.Plays = len(Decompose(rules, slices.DeleteFunc(slices.Clone(hand), rules.IsWild)))
+
+	w := bidWeights
+	if len(hand) > rules.HandSize() {
+		w = landlordWeights
+	}
+	x := w.bias +
+		w.redJoker*float64(counts[card.RankRedJoker]) +
+		w.blackJoker*float64(counts[card.RankBlackJoker]) +
+		w.two*float64(s.Twos+s.Wilds) +
+		w.ace*float64(counts[card.RankA]) +
+		w.bomb*float64(s.Bombs) +
+		w.plays*float64(s.Plays)
+	s.Score = 1 / (1 + math.Exp(-x))
+	return s
+}
@@ -0,0 +1,177 @@
+package rule
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEvaluate checks the counted controls and bombs; the number of plays is covered by TestDecompose.
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rules    RuleSet
+		hand     []card.Card
+		controls int
+		wilds    int
+		bombs    int
+	}{
+		{
+			name:     "jokers and twos are controls",
+			rules:    Classic,
+			hand:     testRuleCards(card.RankBlackJoker, card.Rank2, card.Rank2, card.RankA, card.Rank5),
+			controls: 3,
+		},
+		{
+			name:     "rocket and a bomb of twos",
+			rules:    Classic,
+			hand:     testRuleCards(card.RankRedJoker, card.RankBlackJoker, card.Rank2, card.Rank2, card.Rank2, card.Rank2, card.Rank3),
+			controls: 6,
+			bombs:    2,
+		},
+		{
+			name:     "one rocket is not enough with two decks",
+			rules:    FourPlayer,
+			hand:     testRuleCards(card.RankRedJoker, card.RankBlackJoker, card.Rank9, card.Rank9, card.Rank9, card.Rank9),
+			controls: 2,
+			bombs:    1,
+		},
+		{
+			name:     "wild cards count as controls",
+			rules:    RuleSet{Name: "laizi", WildCards: true, Wild: card.Rank7},
+			hand:     testRuleCards(card.Rank7, card.Rank7, card.Rank3, card.Rank3),
+			controls: 2,
+			wilds:    2,
+		},
+		{
+			name:     "wild twos are not counted twice",
+			rules:    RuleSet{Name: "laizi", WildCards: true, Wild: card.Rank2},
+			hand:     testRuleCards(card.Rank2, card.Rank2, card.Rank2, card.Rank2, card.Rank3),
+			controls: 4,
+			wilds:    4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := Evaluate(tc.rules, tc.hand)
+			assert.Equal(t, tc.controls, s.Controls(), "controls")
+			assert.Equal(t, tc.wilds, s.Wilds, "wilds")
+			assert.Equal(t, tc.bombs, s.Bombs, "bombs")
+			assert.Greater(t, s.Score, 0.0)
+			assert.Less(t, s.Score, 1.0)
+		})
+	}
+}
+
+// TestEvaluate_Weights checks that a hand larger than HandSize is scored with the landlord weights.
+func TestEvaluate_Weights(t *testing.T) {
+	t.Parallel()
+	hand := testRuleCards(
+		card.RankRedJoker, card.Rank2, card.Rank2, card.RankA, card.RankA, card.RankK, card.RankK, card.RankQ, card.RankJ, card.Rank10,
+		card.Rank9, card.Rank9, card.Rank9, card.Rank8, card.Rank7, card.Rank6, card.Rank5, card.Rank4, card.Rank3, card.Rank3,
+	)
+
+	landlord := Evaluate(Classic, hand)
+	bid := Evaluate(RuleSet{HandCards: len(hand)}, hand)
+	assert.Equal(t, bid.Plays, landlord.Plays)
+	assert.InDelta(t, bidWeights.bias-landlordWeights.bias, logit(bid.Score)-logit(landlord.Score), 1e-9)
+}
+
+// TestEvaluate_Score checks that stronger hands get higher scores and bids.
+func TestEvaluate_Score(t *testing.T) {
+	t.Parallel()
+	strong := testRuleCards(
+		card.RankRedJoker, card.RankBlackJoker, card.Rank2, card.Rank2, card.Rank2, card.RankA, card.RankA,
+		card.RankK, card.RankK, card.RankQ, card.RankJ, card.Rank10, card.Rank9, card.Rank8, card.Rank7, card.Rank5, card.Rank5,
+	)
+	weak := testRuleCards(
+		card.Rank3, card.Rank3, card.Rank4, card.Rank4, card.Rank6, card.Rank6, card.Rank8, card.Rank8, card.Rank10,
+		card.Rank10, card.RankQ, card.RankQ, card.Rank5, card.Rank7, card.Rank9, card.RankJ, card.RankK,
+	)
+
+	assert.Equal(t, 3, Evaluate(Classic, strong).BidLevel())
+	assert.Equal(t, 0, Evaluate(Classic, weak).BidLevel())
+
+	// 拿到好的底牌之后评分更高
+	good := Evaluate(Classic, append(slices.Clone(weak), testRuleCards(card.RankRedJoker, card.Rank2, card.RankK)...))
+	poor := Evaluate(Classic, append(slices.Clone(weak), testRuleCards(card.Rank3, card.Rank5, card.Rank8)...))
+	assert.Greater(t, good.Score, poor.Score)
+	assert.Less(t, poor.Score, Evaluate(Classic, weak).Score+0.1)
+}
+
+// TestEvaluate_BidShares checks the properties the hand-picked weights are chosen for on seeded random deals.
+func TestEvaluate_BidShares(t *testing.T) {
+	t.Parallel()
+
+	const deals = 1000
+	rng := rand.New(rand.NewSource(1))
+	var levels [4]int
+	var bid, landlord float64
+	for range deals {
+		deck := card.NewDeck()
+		deck.Shuffle(rng)
+		hand := slices.Clone(deck[:17])
+		s := Evaluate(Classic, hand)
+		levels[s.BidLevel()]++
+		bid += logit(s.Score)
+		landlord += logit(Evaluate(Classic, append(hand, deck[17:20]...)).Score)
+	}
+
+	bids := float64(deals-levels[0]) / deals
+	assert.InDelta(t, 1.0/3, bids, 0.06, "about a third of the hands bid")
+	assert.InDelta(t, 0.1, float64(levels[3])/deals, 0.04, "about a tenth of the hands bid three")
+	assert.InDelta(t, bid/deals, landlord/deals, 0.05, "bottom cards add their average value on top of the bid estimate")
+}
+
+// TestStrength_BidLevel pins the suggested bid for a few typical hands before the bottom cards.
+func TestStrength_BidLevel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hand     []card.Card
+		expected int
+	}{
+		{
+			name: "rocket and a bomb of twos",
+			hand: testRuleCards(card.RankRedJoker, card.RankBlackJoker, card.Rank2, card.Rank2, card.Rank2, card.Rank2, card.RankA,
+				card.RankK, card.RankQ, card.RankJ, card.Rank10, card.Rank9, card.Rank7, card.Rank6, card.Rank5, card.Rank4, card.Rank3),
+			expected: 3,
+		},
+		{
+			name: "black joker, two twos and a straight",
+			hand: testRuleCards(card.RankBlackJoker, card.Rank2, card.Rank2, card.RankA, card.RankA, card.RankK, card.RankK,
+				card.Rank9, card.Rank9, card.Rank9, card.Rank6, card.Rank6, card.Rank5, card.Rank4, card.Rank3, card.Rank7, card.Rank8),
+			expected: 2,
+		},
+		{
+			name: "red joker and two twos with many loose cards",
+			hand: testRuleCards(card.RankRedJoker, card.Rank2, card.Rank2, card.RankA, card.Rank3, card.Rank4, card.Rank5, card.Rank6,
+				card.Rank7, card.Rank8, card.Rank9, card.RankJ, card.RankJ, card.RankQ, card.Rank4, card.Rank8, card.RankK),
+			expected: 1,
+		},
+		{
+			name: "a single two and no jokers",
+			hand: testRuleCards(card.Rank2, card.Rank3, card.Rank4, card.Rank6, card.Rank6, card.Rank8, card.Rank9, card.Rank10,
+				card.RankJ, card.RankJ, card.RankQ, card.RankK, card.Rank5, card.Rank7, card.Rank9, card.RankA, card.Rank3),
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s := Evaluate(Classic, tc.hand)
+			assert.Equal(t, tc.expected, s.BidLevel(), "score %.2f with %d plays", s.Score, s.Plays)
+		})
+	}
+}
+
+// logit is the inverse of the logistic function Evaluate applies to the weighted sum.
+func logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
@@ -1,41 +1,103 @@
 package rule
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/palemoky/fight-the-landlord-go/internal/card"
 )
 
-// isRocket 王炸
-func isRocket(analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
-	if len(cards) == 2 && analysis.counts[card.RankBlackJoker] == 1 && analysis.counts[card.RankRedJoker] == 1 {
+// isRocket 王炸，多副牌时要所有的大小王
+func isRocket(rules RuleSet, analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
+	decks := rules.DeckCount()
+	if len(cards) == 2*decks && analysis.counts[card.RankBlackJoker] == decks && analysis.counts[card.RankRedJoker] == decks {
 		return ParsedHand{Type: Rocket, KeyRank: card.RankRedJoker, Cards: cards}, true
 	}
 	return ParsedHand{}, false
 }
 
-// isBomb 炸弹
-func isBomb(analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
-	if len(analysis.fours) == 1 && len(cards) == 4 {
-		return ParsedHand{Type: Bomb, KeyRank: analysis.fours[0], Cards: cards}, true
+// isBomb 炸弹：四张或更多同点数的牌，最多是所有牌中这个点数的张数
+// 有癞子代替时是软炸，四张都是癞子时是纯癞子炸弹。
+func isBomb(rules RuleSet, analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
+	if len(analysis.counts) == 1 && len(cards) >= 4 && len(cards) <= 4*rules.DeckCount() {
+		hand := ParsedHand{Type: Bomb, KeyRank: cards[0].EffectiveRank(), Length: len(cards), Cards: cards}
+		switch {
+		case !slices.ContainsFunc(cards, func(c card.Card) bool { return !rules.IsWild(c) }):
+			hand.Type = WildBomb
+		case slices.ContainsFunc(cards, card.Card.Substituted):
+			hand.Type = SoftBomb
+		}
+		return hand, true
 	}
 	return ParsedHand{}, false
 }
 
-// isFourWithKickers 四带二
-func isFourWithKickers(analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
+// isFourWithKickers 四带二、四带两对
+// JJJJQQQQ 这样的牌两个四张都可以作为主体，大的在前。
+func isFourWithKickers(rules RuleSet, analysis HandAnalysis, cards []card.Card) []ParsedHand {
 	cardLen := len(cards)
-	if len(analysis.fours) == 1 {
-		hand := ParsedHand{KeyRank: analysis.fours[0], Cards: cards}
-		if cardLen == 6 && (len(analysis.ones) == 2 || len(analysis.pairs) == 1) { // AAAABB、AAAABC
-			// 四带二，可以带两张单牌，也可以带一个对子(不算四带两对)
+	if cardLen != 6 && cardLen != 8 {
+		return nil
+	}
+	var hands []ParsedHand
+	for i := len(analysis.fours) - 1; i >= 0; i-- {
+		r := analysis.fours[i]
+		kickers := kickerCounts(analysis, 4, r)
+		hand := ParsedHand{KeyRank: r, Cards: cards}
+		if cardLen == 6 && validFourKickers(rules, kickers) { // AAAABC、AAAABB
 			hand.Type = FourWithTwo
-			return hand, true
+			hands = append(hands, hand)
 		}
-		if cardLen == 8 && len(analysis.pairs) == 2 { // AAAABBCC、AAAABBBB
+		if cardLen == 8 && !rules.NoFourWithTwoPairs && validPairKickers(rules, kickers) { // AAAABBCC、AAAABBBB
 			hand.Type = FourWithTwoPairs
-			return hand, true
+			hands = append(hands, hand)
 		}
 	}
-	return ParsedHand{}, false
+	return hands
+}
+
+// validFourKickers 四带二的两张带牌：两张不同的单牌，或者规则允许时的一对
+func validFourKickers(rules RuleSet, kickers map[card.Rank]int) bool {
+	if len(kickers) == 1 {
+		return !rules.FourWithTwoNoPair
+	}
+	return !(rules.NoRocketKickers && hasRocket(kickers))
+}
+
+// validPairKickers 带对子的带牌：每个点数都是一对，规则允许时也可以是拆成两对的四张
+func validPairKickers(rules RuleSet, kickers map[card.Rank]int) bool {
+	for _, n := range kickers {
+		if n != 2 && !(n == 4 && rules.BombKickers) {
+			return false
+		}
+	}
+	return true
+}
+
+// validSingleKickers 飞机带单的翅膀：任意单牌，可以有相同点数的牌，例如 333444+55
+// 规则允许时翅膀才能和机身同点数、才能是一整个炸弹，大小王不能同时作为翅膀。
+func validSingleKickers(rules RuleSet, kickers map[card.Rank]int, body []card.Rank) bool {
+	for r, n := range kickers {
+		if (n == 4 && !rules.BombKickers) || (!rules.PlaneKickersShareRank && slices.Contains(body, r)) {
+			return false
+		}
+	}
+	return !(rules.NoRocketKickers && hasRocket(kickers))
+}
+
+// kickerCounts 去掉主体之后剩下的带牌，body 中的每个点数去掉 width 张
+func kickerCounts(analysis HandAnalysis, width int, body ...card.Rank) map[card.Rank]int {
+	kickers := maps.Clone(analysis.counts)
+	for _, r := range body {
+		if kickers[r] -= width; kickers[r] == 0 {
+			delete(kickers, r)
+		}
+	}
+	return kickers
+}
+
+func hasRocket(kickers map[card.Rank]int) bool {
+	return kickers[card.RankBlackJoker] > 0 && kickers[card.RankRedJoker] > 0
 }
 
 // isTrioWithKickers 三带X
@@ -56,33 +118,54 @@ func isTrioWithKickers(analysis HandAnalysis, cards []card.Card) (ParsedHand, bo
 }
 
 // isPlane 飞机
-func isPlane(analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
-	cardLen, planeLen := len(cards), len(analysis.trios)
-	if isContinuous(analysis.trios) && planeLen >= 2 {
-		hand := ParsedHand{KeyRank: analysis.trios[0], Length: planeLen, Cards: cards}
-		// 飞机不带翅膀
-		if planeLen*3 == cardLen { // AAABBB+
-			hand.Type = Plane
-			return hand, true
+// 牌数决定了飞机的长度：不带是 3 张一节，带单是 4 张一节，带对是 5 张一节。
+// 返回所有的取法，同一牌型中点数大的在前。
+func isPlane(rules RuleSet, analysis HandAnalysis, cards []card.Card) []ParsedHand {
+	cardLen := len(cards)
+	var ranks []card.Rank // 可以作为飞机机身的点数
+	for r, n := range analysis.counts {
+		if n >= 3 && r < card.Rank2 {
+			ranks = append(ranks, r)
 		}
-		// 飞机带单
-		if planeLen*4 == cardLen && len(analysis.ones) == planeLen { // AAABBBCD+、AAABBAC+、AAABBBCC+
-			hand.Type = PlaneWithSingles
-			return hand, true
+	}
+	slices.Sort(ranks)
+
+	var hands []ParsedHand
+	for _, t := range []struct {
+		handType HandType
+		width    int // 每节的张数
+	}{{Plane, 3}, {PlaneWithSingles, 4}, {PlaneWithPairs, 5}} {
+		planeLen := cardLen / t.width
+		if cardLen%t.width != 0 || planeLen < 2 {
+			continue
 		}
-		// 飞机带对
-		if planeLen*5 == cardLen && len(analysis.pairs) == planeLen { // AAABBBCCDD+
-			hand.Type = PlaneWithPairs
-			return hand, true
+		for i := len(ranks) - planeLen; i >= 0; i-- {
+			body := ranks[i : i+planeLen]
+			if !isContinuous(body) {
+				continue
+			}
+			kickers := kickerCounts(analysis, 3, body...)
+			valid := false
+			switch t.handType {
+			case Plane: // AAABBB+
+				valid = len(kickers) == 0
+			case PlaneWithSingles: // AAABBBCD+
+				valid = validSingleKickers(rules, kickers, body)
+			case PlaneWithPairs: // AAABBBCCDD+
+				valid = validPairKickers(rules, kickers)
+			}
+			if valid {
+				hands = append(hands, ParsedHand{Type: t.handType, KeyRank: body[0], Length: planeLen, Cards: cards})
+			}
 		}
 	}
-	return ParsedHand{}, false
+	return hands
 }
 
 // isStraight 顺子
-func isStraight(analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
+func isStraight(rules RuleSet, analysis HandAnalysis, cards []card.Card) (ParsedHand, bool) {
 	cardLen := len(cards)
-	if isContinuous(analysis.ones) && len(analysis.ones) == cardLen && cardLen >= 5 { // ABCDE+
+	if isContinuous(analysis.ones) && len(analysis.ones) == cardLen && cardLen >= rules.MinStraightLen() { // ABCDE+
 		return ParsedHand{Type: Straight, KeyRank: analysis.ones[0], Length: cardLen, Cards: cards}, true
 	}
 	return ParsedHand{}, false
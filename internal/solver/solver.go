@@ -0,0 +1,261 @@
+package solver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// ErrNodeLimit 搜索的局面数超过了上限
+var ErrNodeLimit = errors.New("搜索超出局面数上限")
+
+// Side 定义对局的一方
+type Side int
+
+const (
+	Landlord Side = iota // 地主
+	Farmers              // 农民
+)
+
+func (s Side) String() string {
+	if s == Landlord {
+		return "地主"
+	}
+	return "农民"
+}
+
+// Position 明牌局面：所有人的手牌都是已知的
+type Position struct {
+	Rules    rule.RuleSet    // 牌型规则
+	Hands    [][]card.Card   // 每个座位的手牌
+	Landlord int             // 地主的座位
+	Turn     int             // 轮到出牌的座位
+	Last     rule.ParsedHand // 桌面上需要压过的牌，自由出牌时为空
+	LastSeat int             // 打出 Last 的座位
+}
+
+// Move 一步出牌，Cards 为空表示 PASS
+type Move struct {
+	Seat  int
+	Cards []card.Card
+	Hand  rule.ParsedHand // 出牌的牌型，PASS 时为空
+}
+
+// Result 求解的结果
+type Result struct {
+	Winner Side   // 双方都走最优时的胜方
+	Line   []Move // 一条最优的出牌路线，直到有人出完
+	Nodes  int    // 搜索过的局面数
+}
+
+// Solver 明牌残局求解器
+// 它在与或树上做布尔 alpha-beta 搜索：轮到的一方只要有一步能赢就是胜局，
+// 所有走法都输才是败局。置换表以各座位的点数分布和桌面牌型为键，花色不影响结果。
+type Solver struct {
+	MaxNodes int // 最多搜索的局面数，0 表示不限
+
+	table map[string]Side
+	nodes int
+}
+
+// New 创建一个求解器，maxNodes 不大于 0 时不限制搜索规模
+func New(maxNodes int) *Solver {
+	return &Solver{MaxNodes: maxNodes}
+}
+
+// Solve 使用不限规模的求解器求解 pos
+func Solve(pos Position) (Result, error) {
+	return New(0).Solve(pos)
+}
+
+// Solve 求出 pos 在双方都走最优时的胜方和一条最优路线
+// 搜索超出 MaxNodes 时返回 ErrNodeLimit。
+func (s *Solver) Solve(pos Position) (Result, error) {
+	st, err := newState(pos)
+	if err != nil {
+		return Result{}, err
+	}
+	s.table = make(map[string]Side)
+	s.nodes = 0
+
+	if w, done := st.winner(); done {
+		return Result{Winner: w, Nodes: s.nodes}, nil
+	}
+	winner, ok := s.search(st)
+	if !ok {
+		return Result{Nodes: s.nodes}, ErrNodeLimit
+	}
+
+	// 沿着置换表走出最优路线：胜方走一步能赢的棋，败方走第一步
+	var line []Move
+	for {
+		if _, done := st.winner(); done {
+			break
+		}
+		moves := st.moves()
+		next := moves[0]
+		if st.side(st.turn) == winner {
+			for _, m := range moves {
+				child := st.apply(m)
+				if w, ok := s.search(child); ok && w == winner {
+					next = m
+					break
+				}
+			}
+		}
+		line = append(line, Move{Seat: st.turn, Cards: next.Cards, Hand: next})
+		st = st.apply(next)
+	}
+	return Result{Winner: winner, Line: line, Nodes: s.nodes}, nil
+}
+
+// search 返回 st 的胜方；超出局面数上限时 ok 为 false
+func (s *Solver) search(st *state) (winner Side, ok bool) {
+	if w, done := st.winner(); done {
+		return w, true
+	}
+	key := st.key()
+	if w, found := s.table[key]; found {
+		return w, true
+	}
+	s.nodes++
+	if s.MaxNodes > 0 && s.nodes > s.MaxNodes {
+		return 0, false
+	}
+
+	me := st.side(st.turn)
+	winner = other(me)
+	for _, m := range st.moves() {
+		w, ok := s.search(st.apply(m))
+		if !ok {
+			return 0, false
+		}
+		if w == me {
+			winner = me
+			break
+		}
+	}
+	s.table[key] = winner
+	return winner, true
+}
+
+func other(side Side) Side {
+	if side == Landlord {
+		return Farmers
+	}
+	return Landlord
+}
+
+// state 搜索中的局面
+type state struct {
+	rules    rule.RuleSet
+	hands    [][]card.Card
+	landlord int
+	turn     int
+	last     rule.ParsedHand
+	lastSeat int
+}
+
+func newState(pos Position) (*state, error) {
+	n := len(pos.Hands)
+	if n < 2 {
+		return nil, fmt.Errorf("至少需要 2 个座位的手牌，实际为 %d 个", n)
+	}
+	for _, seat := range []int{pos.Landlord, pos.Turn} {
+		if seat < 0 || seat >= n {
+			return nil, fmt.Errorf("座位 %d 不存在", seat)
+		}
+	}
+	st := &state{
+		rules:    pos.Rules,
+		hands:    make([][]card.Card, n),
+		landlord: pos.Landlord,
+		turn:     pos.Turn,
+		last:     pos.Last,
+		lastSeat: pos.LastSeat,
+	}
+	for i, h := range pos.Hands {
+		st.hands[i] = slices.Clone(h)
+	}
+	if st.last.IsEmpty() || st.lastSeat == st.turn || st.lastSeat < 0 || st.lastSeat >= n {
+		st.last = rule.ParsedHand{}
+		st.lastSeat = st.turn
+	}
+	return st, nil
+}
+
+func (st *state) side(seat int) Side {
+	if seat == st.landlord {
+		return Landlord
+	}
+	return Farmers
+}
+
+// winner 有人出完牌时返回他所在的一方
+func (st *state) winner() (Side, bool) {
+	for seat, h := range st.hands {
+		if len(h) == 0 {
+			return st.side(seat), true
+		}
+	}
+	return 0, false
+}
+
+// moves 当前座位的全部走法：能一手出完的排在最前，其余按出牌张数从多到少，PASS（空的 ParsedHand）排在最后
+func (st *state) moves() []rule.ParsedHand {
+	hand := st.hands[st.turn]
+	plays := rule.EnumerateLegalPlays(st.rules, hand, st.last)
+	slices.SortStableFunc(plays, func(a, b rule.ParsedHand) int {
+		return cmp.Compare(len(b.Cards), len(a.Cards))
+	})
+
+	if !st.last.IsEmpty() {
+		plays = append(plays, rule.ParsedHand{})
+	}
+	return plays
+}
+
+// apply 返回当前座位打出 hand 之后的新局面
+func (st *state) apply(hand rule.ParsedHand) *state {
+	next := &state{
+		rules:    st.rules,
+		hands:    slices.Clone(st.hands),
+		landlord: st.landlord,
+		turn:     (st.turn + 1) % len(st.hands),
+		last:     st.last,
+		lastSeat: st.lastSeat,
+	}
+	if !hand.IsEmpty() {
+		next.last = hand
+		next.lastSeat = st.turn
+		next.hands[st.turn] = card.RemoveRanks(st.hands[st.turn], hand.Cards)
+	}
+	if next.turn == next.lastSeat {
+		next.last = rule.ParsedHand{} // 其他人都不要，开始新的一轮
+	}
+	return next
+}
+
+// key 置换表的键：各座位的点数分布、轮到谁、桌面牌型以及出牌的人
+func (st *state) key() string {
+	var b strings.Builder
+	for _, h := range st.hands {
+		counts := card.CountRanks(h)
+		for r := card.Rank3; r <= card.RankRedJoker; r++ {
+			b.WriteByte(byte(counts[r]))
+		}
+	}
+	b.WriteByte(byte(st.turn))
+	if !st.last.IsEmpty() {
+		b.WriteByte(byte(st.lastSeat))
+		b.WriteByte(byte(st.last.Type))
+		b.WriteByte(byte(st.last.KeyRank))
+		b.WriteByte(byte(st.last.Length))
+	}
+	return b.String()
+}
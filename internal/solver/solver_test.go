@@ -0,0 +1,155 @@
+package solver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCards creates distinct cards, cycling suits for repeated ranks.
+func testCards(ranks ...card.Rank) []card.Card {
+	seen := make(map[card.Rank]int)
+	cards := make([]card.Card, len(ranks))
+	for i, r := range ranks {
+		suit := card.Suit(seen[r])
+		if r >= card.RankBlackJoker {
+			suit = card.Joker
+		}
+		cards[i] = card.Card{Rank: r, Suit: suit}
+		seen[r]++
+	}
+	return cards
+}
+
+func cardRanks(cards []card.Card) []card.Rank {
+	ranks := make([]card.Rank, len(cards))
+	for i, c := range cards {
+		ranks[i] = c.Rank
+	}
+	return ranks
+}
+
+// TestSolve uses a table of small endgames with known outcomes.
+func TestSolve(t *testing.T) {
+	testCases := []struct {
+		name      string
+		pos       Position
+		winner    Side
+		firstMove []card.Rank
+	}{
+		{
+			name: "landlord plays out in one hand",
+			pos: Position{
+				Hands:    [][]card.Card{testCards(card.Rank9, card.Rank9, card.Rank9, card.Rank4), testCards(card.Rank2), testCards(card.RankA)},
+				Landlord: 0,
+			},
+			winner:    Landlord,
+			firstMove: []card.Rank{card.Rank9, card.Rank9, card.Rank9, card.Rank4},
+		},
+		{
+			name: "farmer leads the 2 so the teammate can go out",
+			pos: Position{
+				Hands:    [][]card.Card{testCards(card.RankBlackJoker), testCards(card.Rank2, card.Rank3), testCards(card.RankA)},
+				Landlord: 2,
+				Turn:     1,
+			},
+			winner:    Farmers,
+			firstMove: []card.Rank{card.Rank2},
+		},
+		{
+			name: "farmers cannot stop a high single",
+			pos: Position{
+				Hands:    [][]card.Card{testCards(card.Rank2), testCards(card.Rank3, card.Rank4), testCards(card.Rank5, card.Rank5)},
+				Landlord: 0,
+				Turn:     1,
+			},
+			winner: Landlord,
+		},
+		{
+			name: "landlord holding bomb and rocket always wins",
+			pos: Position{
+				Hands: [][]card.Card{
+					testCards(card.Rank7, card.Rank7, card.Rank7, card.Rank7, card.RankRedJoker),
+					testCards(card.Rank3, card.Rank3),
+					testCards(card.Rank6, card.Rank8),
+				},
+				Landlord: 0,
+				Last:     rule.ParsedHand{Type: rule.Single, KeyRank: card.Rank2, Length: 1, Cards: testCards(card.Rank2)},
+				LastSeat: 2,
+			},
+			winner: Landlord,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := Solve(tc.pos)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.winner, result.Winner)
+			require.NotEmpty(t, result.Line)
+			if tc.firstMove != nil {
+				assert.ElementsMatch(t, tc.firstMove, cardRanks(result.Line[0].Cards))
+			}
+			checkLine(t, tc.pos, result)
+		})
+	}
+}
+
+// checkLine replays the winning line and makes sure every move is legal
+// and that the game ends with the winning side going out.
+func checkLine(t *testing.T, pos Position, result Result) {
+	t.Helper()
+	st, err := newState(pos)
+	require.NoError(t, err)
+
+	for _, m := range result.Line {
+		require.Equal(t, st.turn, m.Seat)
+		if len(m.Cards) == 0 {
+			require.False(t, st.last.IsEmpty(), "cannot pass when leading")
+		} else {
+			require.True(t, card.ContainsCards(st.hands[m.Seat], m.Cards))
+			played, err := rule.ParseHand(rule.Classic, m.Cards)
+			require.NoError(t, err)
+			require.True(t, slices.ContainsFunc(played, m.Hand.SameAs))
+			require.True(t, st.last.IsEmpty() || rule.CanBeat(rule.Classic, m.Hand, st.last))
+		}
+		st = st.apply(m.Hand)
+	}
+	winner, done := st.winner()
+	require.True(t, done, "line should end the game")
+	assert.Equal(t, result.Winner, winner)
+}
+
+// TestSolver_NodeLimit checks that the search stops once the budget is spent.
+func TestSolver_NodeLimit(t *testing.T) {
+	pos := Position{
+		Hands: [][]card.Card{
+			testCards(card.Rank3, card.Rank5, card.Rank7, card.Rank9, card.RankJ, card.RankK, card.Rank2),
+			testCards(card.Rank4, card.Rank6, card.Rank8, card.Rank10, card.RankQ, card.RankA, card.RankBlackJoker),
+			testCards(card.Rank3, card.Rank4, card.Rank5, card.Rank6, card.Rank7, card.RankRedJoker),
+		},
+	}
+
+	_, err := New(5).Solve(pos)
+	assert.ErrorIs(t, err, ErrNodeLimit)
+
+	result, err := New(0).Solve(pos)
+	require.NoError(t, err)
+	assert.Greater(t, result.Nodes, 5)
+	checkLine(t, pos, result)
+}
+
+// TestSolve_InvalidPosition rejects positions that do not describe a game.
+func TestSolve_InvalidPosition(t *testing.T) {
+	_, err := Solve(Position{Hands: [][]card.Card{testCards(card.Rank3)}})
+	assert.Error(t, err)
+
+	_, err = Solve(Position{Hands: [][]card.Card{testCards(card.Rank3), testCards(card.Rank4), testCards(card.Rank5)}, Turn: 3})
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/timer"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+const (
+	humanSeat    = 0                      // 人类玩家的座位
+	botMoveDelay = 800 * time.Millisecond // 电脑出牌前的停顿，方便看清每一手牌
+)
+
+// humanAgent 把键盘输入转交给游戏，让人类玩家和电脑玩家一样通过 game.Agent 驱动
+type humanAgent struct {
+	bids  chan game.BidAction
+	plays chan rule.ParsedHand
+}
+
+func newHumanAgent() *humanAgent {
+	return &humanAgent{
+		bids:  make(chan game.BidAction, 1),
+		plays: make(chan rule.ParsedHand, 1),
+	}
+}
+
+// Bid 等待玩家输入叫地主指令
+func (h *humanAgent) Bid(game.PlayerView) game.BidAction {
+	return <-h.bids
+}
+
+// Play 等待玩家输入出牌
+func (h *humanAgent) Play(game.PlayerView) rule.ParsedHand {
+	return <-h.plays
+}
+
+// turnStartMsg 通知 UI 开始驱动当前座位
+type turnStartMsg struct{}
+
+// agentBidMsg 某个座位的 Agent 做出的叫地主决定
+type agentBidMsg struct {
+	seat   int
+	action game.BidAction
+}
+
+// agentPlayMsg 某个座位的 Agent 做出的出牌决定，hand 为空表示 PASS
+type agentPlayMsg struct {
+	seat int
+	hand rule.ParsedHand
+}
+
+// nextTurn 让当前座位的 Agent 在后台做决定；轮到人类玩家时重置计时器
+func (m *model) nextTurn() tea.Cmd {
+	if _, isOver := m.game.CheckWinner(); isOver && m.game.Phase == game.PhasePlaying {
+		_, _ = m.match.Settle() // 记入比赛总账
+		return nil
+	}
+
+	seat, phase := m.game.CurrentTurn, m.game.Phase
+	agent, view := m.agents[seat], m.game.View(seat)
+	m.updatePlaceholder()
+	m.analyze()
+
+	var cmds []tea.Cmd
+	delay := botMoveDelay
+	if seat == humanSeat {
+		delay = 0
+		m.awaitingHuman = true
+		m.timer = timer.NewWithInterval(game.PlayerTurnTimeout, time.Second)
+		cmds = append(cmds, m.timer.Start())
+	}
+	cmds = append(cmds, func() tea.Msg {
+		time.Sleep(delay)
+		if phase == game.PhaseBidding {
+			return agentBidMsg{seat: seat, action: agent.Bid(view)}
+		}
+		return agentPlayMsg{seat: seat, hand: agent.Play(view)}
+	})
+	return tea.Batch(cmds...)
+}
+
+// applyBid 应用 Agent 的叫地主决定，不合法时按不叫处理
+func (m *model) applyBid(msg agentBidMsg) tea.Cmd {
+	if m.game.Phase != game.PhaseBidding || msg.seat != m.game.CurrentTurn {
+		return nil
+	}
+	if err := m.game.Bid(msg.action); err != nil {
+		_ = m.game.Bid(game.BidPass)
+	}
+	return m.nextTurn()
+}
+
+// applyPlay 应用 Agent 的出牌决定，不合法时按超时托管处理
+func (m *model) applyPlay(msg agentPlayMsg) tea.Cmd {
+	if m.game.Phase != game.PhasePlaying || msg.seat != m.game.CurrentTurn {
+		return nil
+	}
+	if err := m.playOrPass(msg.hand); err != nil {
+		_ = m.game.Timeout()
+	}
+	return m.nextTurn()
+}
+
+func (m *model) playOrPass(hand rule.ParsedHand) error {
+	if hand.IsEmpty() {
+		return m.game.Pass()
+	}
+	return m.game.PlayHand(hand)
+}
+
+// timeoutHand 超时托管的出牌，PASS 时为空
+func (m *model) timeoutHand() rule.ParsedHand {
+	cards := m.game.TimeoutMove()
+	if len(cards) == 0 {
+		return rule.ParsedHand{}
+	}
+	options, err := m.game.Interpretations(cards)
+	if err != nil {
+		return rule.ParsedHand{}
+	}
+	return options[0]
+}
+
+// undo 练习模式中悔棋：撤销到人类玩家的上一手之前，中间电脑的出牌一起撤销
+func (m *model) undo() tea.Cmd {
+	if !m.awaitingHuman || m.game.Phase != game.PhasePlaying {
+		return nil
+	}
+	if !slices.ContainsFunc(m.game.Moves, func(mv game.Move) bool { return mv.Seat == humanSeat }) {
+		m.error = "还没有可以悔的棋"
+		return nil
+	}
+	for {
+		seat := m.game.Moves[len(m.game.Moves)-1].Seat
+		if err := m.game.Undo(); err != nil {
+			m.error = err.Error()
+			return nil
+		}
+		if seat == humanSeat {
+			break
+		}
+	}
+	return m.restartHumanTurn()
+}
+
+// redo 练习模式中重做：重新走回悔掉的人类玩家的一手以及之后电脑的出牌
+func (m *model) redo() tea.Cmd {
+	if !m.awaitingHuman || !m.game.CanRedo() {
+		return nil
+	}
+	for {
+		if err := m.game.Redo(); err != nil {
+			m.error = err.Error()
+			break
+		}
+		if _, isOver := m.game.CheckWinner(); isOver || m.game.CurrentTurn == humanSeat || !m.game.CanRedo() {
+			break
+		}
+	}
+	if m.game.CurrentTurn == humanSeat {
+		if _, isOver := m.game.CheckWinner(); !isOver {
+			return m.restartHumanTurn()
+		}
+	}
+	// 不再轮到人类玩家，结束正在等待输入的 Agent，交给下一个座位
+	m.awaitingHuman = false
+	m.human.plays <- rule.ParsedHand{}
+	return m.nextTurn()
+}
+
+// restartHumanTurn 悔棋后重新等待人类玩家出牌，正在等待输入的 Agent 继续使用
+func (m *model) restartHumanTurn() tea.Cmd {
+	m.error = ""
+	m.choices = nil
+	m.input.Reset()
+	m.updatePlaceholder()
+	m.analyze()
+	m.timer = timer.NewWithInterval(game.PlayerTurnTimeout, time.Second)
+	return m.timer.Start()
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/palemoky/fight-the-landlord-go/internal/card"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/match"
+	"github.com/palemoky/fight-the-landlord-go/internal/record"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
+)
+
+// historyLines 出牌记录最多显示的行数
+const historyLines = 5
+
+// eventLog 订阅比赛事件，记录当前这一局每一步的说明
+type eventLog struct {
+	lines []string
+	moves []int // 每一手出牌或 PASS 在 lines 中开始的位置，悔棋时据此删掉这一手的说明
+}
+
+// watchMatch 开始记录 mt 中每一局的事件，新的一局发牌时清空上一局的记录
+func watchMatch(mt *match.Match) *eventLog {
+	l := &eventLog{}
+	mt.Subscribe(func(g *game.Game, e game.Event) {
+		switch e := e.(type) {
+		case game.Dealt:
+			if !e.Redeal {
+				l.lines, l.moves = nil, nil
+			}
+		case game.CardsPlayed, game.Passed:
+			l.moves = append(l.moves, len(l.lines))
+		case game.MoveUndone:
+			if n := len(l.moves); n > 0 {
+				l.lines, l.moves = l.lines[:l.moves[n-1]], l.moves[:n-1]
+			}
+			return
+		}
+		if line := describeEvent(g, e); line != "" {
+			l.lines = append(l.lines, line)
+		}
+	})
+	return l
+}
+
+// describeEvent 把事件转换成一行说明
+func describeEvent(g *game.Game, e game.Event) string {
+	name := func(seat int) string { return g.Players[seat].Name }
+	switch e := e.(type) {
+	case game.Dealt:
+		switch {
+		case e.Redeal && e.Wild != 0:
+			return fmt.Sprintf("没有人叫地主，重新发牌，癞子是 %s", e.Wild)
+		case e.Redeal:
+			return "没有人叫地主，重新发牌"
+		case e.Wild != 0:
+			return fmt.Sprintf("发牌，癞子是 %s", e.Wild)
+		default:
+			return "发牌"
+		}
+	case game.BidPlaced:
+		return fmt.Sprintf("%s: %s", name(e.Seat), e.Action)
+	case game.LandlordChosen:
+		return fmt.Sprintf("%s 成为地主，底分 %d", name(e.Seat), e.BaseScore)
+	case game.CardsPlayed:
+		return fmt.Sprintf("%s: %s", name(e.Seat), formatHand(e.Hand))
+	case game.Passed:
+		return fmt.Sprintf("%s: PASS", name(e.Seat))
+	case game.TrickReset:
+		return fmt.Sprintf("新的一轮，%s 先出", name(e.Leader))
+	case game.BombPlayed:
+		return fmt.Sprintf("💣 倍数 ×%d", e.Multiplier)
+	case game.GameOver:
+		return fmt.Sprintf("%s 出完了牌", name(e.Winner))
+	}
+	return ""
+}
+
+// formatHand 把一手牌写成出牌记法，带牌的牌型用 + 分开主体和带牌，例如 444555666+333
+func formatHand(h rule.ParsedHand) string {
+	body := h.Body()
+	kickers := make([]card.Rank, 0, len(h.Cards))
+	for _, c := range h.Cards {
+		kickers = append(kickers, c.EffectiveRank())
+	}
+	for _, r := range body {
+		if i := slices.Index(kickers, r); i >= 0 {
+			kickers = slices.Delete(kickers, i, i+1)
+		}
+	}
+	if len(kickers) == 0 {
+		return record.FormatRanks(body)
+	}
+	return record.FormatRanks(body) + "+" + record.FormatRanks(kickers)
+}
+
+// renderHistory 显示最近的出牌记录
+func (m model) renderHistory() string {
+	var lines []string
+	if m.history != nil {
+		lines = m.history.lines[max(len(m.history.lines)-historyLines, 0):]
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, append([]string{"出牌记录"}, lines...)...)
+	return boxStyle.Height(historyLines + 1).Render(content)
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/match"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatchMatch checks that the history starts with the deal of every hand, including the first.
+func TestWatchMatch(t *testing.T) {
+	mt := match.New(match.Config{Seed: 3})
+	history := watchMatch(mt)
+
+	g, err := mt.NextGame()
+	require.NoError(t, err)
+	require.NotEmpty(t, history.lines)
+	assert.Equal(t, "发牌", history.lines[0])
+
+	require.NoError(t, g.Bid(game.BidThree))
+	assert.Greater(t, len(history.lines), 1)
+
+	for {
+		if _, isOver := g.CheckWinner(); isOver {
+			break
+		}
+		if cards := g.TimeoutMove(); len(cards) > 0 {
+			require.NoError(t, g.Play(cards))
+		} else {
+			require.NoError(t, g.Pass())
+		}
+	}
+	_, err = mt.NextGame()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"发牌"}, history.lines, "a new hand clears the previous history")
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/record"
+	"github.com/palemoky/fight-the-landlord-go/internal/utils"
+)
+
+// openView 回放时所有人明牌
+const openView = -1
+
+// replayModel 回放一局棋谱，可以逐步前进后退，切换明牌或某个座位的视角
+type replayModel struct {
+	model               // 复用对局界面的渲染函数，game 指向当前步的局面
+	frames []*game.Game // 每一步之后的局面，frames[0] 是刚发完牌的局面
+	steps  []string     // 每一步的说明
+	step   int
+	seat   int // 视角座位，openView 表示明牌
+}
+
+// loadReplay 读取棋谱文件并创建回放界面
+func loadReplay(path string) (replayModel, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return replayModel{}, err
+	}
+	defer f.Close()
+
+	rec, err := record.Parse(f)
+	if err != nil {
+		return replayModel{}, err
+	}
+	return newReplayModel(rec)
+}
+
+// newReplayModel 按棋谱重建每一步之后的局面
+func newReplayModel(rec *record.Record) (replayModel, error) {
+	m := replayModel{seat: openView}
+	total := len(rec.Bids) + len(rec.Moves)
+	for i := 0; i <= total; i++ {
+		prefix := *rec
+		prefix.Bids = rec.Bids[:min(i, len(rec.Bids))]
+		prefix.Moves = rec.Moves[:max(i-len(rec.Bids), 0)]
+		g, err := game.FromRecord(&prefix)
+		if err != nil {
+			return replayModel{}, err
+		}
+		m.frames = append(m.frames, g)
+	}
+
+	m.steps = append(m.steps, "发牌")
+	for _, bid := range rec.Bids {
+		action, _ := game.ParseBidNotation(bid.Action) // FromRecord 已经校验过
+		m.steps = append(m.steps, fmt.Sprintf("%s: %s", m.frames[0].Players[bid.Seat].Name, action))
+	}
+	for _, mv := range rec.Moves {
+		desc := "PASS"
+		if len(mv.Ranks) > 0 {
+			desc = "出 " + record.FormatRanks(mv.Ranks)
+		}
+		m.steps = append(m.steps, fmt.Sprintf("%s: %s", m.frames[0].Players[mv.Seat].Name, desc))
+	}
+	m.game = m.frames[0]
+	return m, nil
+}
+
+func (m replayModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m replayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		case "right", "l", " ":
+			m.step = min(m.step+1, len(m.frames)-1)
+		case "left", "h":
+			m.step = max(m.step-1, 0)
+		case "home":
+			m.step = 0
+		case "end":
+			m.step = len(m.frames) - 1
+		case "tab":
+			// 明牌 -> 座位 1 -> 座位 2 -> 座位 3 -> 明牌
+			m.seat++
+			if m.seat >= len(m.game.Players) {
+				m.seat = openView
+			}
+		}
+		m.game = m.frames[m.step]
+	}
+	return m, nil
+}
+
+func (m replayModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	viewName := utils.Ternary(m.seat == openView, "明牌", "")
+	if m.seat != openView {
+		viewName = m.game.Players[m.seat].Name + " 的视角"
+	}
+	title := titleStyle("REPLAY")
+	progress := fmt.Sprintf("第 %d/%d 步  %s  [%s]", m.step, len(m.frames)-1, m.steps[m.step], viewName)
+	help := "←/→ 单步  Home/End 开头/结尾  Tab 切换视角  Esc 退出"
+	header := lipgloss.JoinVertical(lipgloss.Center, title, progress)
+
+	counter := m.renderCardCounter(m.seat)
+	landlordCards := m.renderLandlordCards()
+	top := lipgloss.JoinVertical(lipgloss.Center, header, lipgloss.JoinHorizontal(lipgloss.Center, counter, landlordCards))
+
+	var seats []string
+	for idx := range m.game.Players {
+		seats = append(seats, m.renderReplaySeat(idx))
+	}
+	middle := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Left, seats...), " ", m.renderLastPlay())
+
+	content := lipgloss.JoinVertical(lipgloss.Center, top, middle, promptStyle.Render(help))
+	return docStyle.Render(lipgloss.PlaceHorizontal(m.width, lipgloss.Center, content))
+}
+
+// renderReplaySeat 显示一个座位：明牌或自己的视角时显示手牌，否则只显示剩余张数
+func (m replayModel) renderReplaySeat(idx int) string {
+	p := m.game.Players[idx]
+	icon := utils.Ternary(p.IsLandlord, LandlordIcon, FarmerIcon)
+
+	nameStyle := lipgloss.NewStyle()
+	if m.game.CurrentTurn == idx {
+		nameStyle = nameStyle.Foreground(lipgloss.Color("220")).Bold(true)
+	}
+	var name strings.Builder
+	name.WriteString(nameStyle.Render(fmt.Sprintf(" %s %s", icon, p.Name)))
+	name.WriteString(fmt.Sprintf("  🃏 剩余: %d", len(p.Hand)))
+	name.WriteString(m.renderBids(idx))
+
+	content := name.String()
+	if m.seat == openView || m.seat == idx {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, m.renderFancyHand(p.Hand))
+	}
+	return boxStyle.Render(content)
+}
@@ -3,6 +3,10 @@ package ui
 import (
 	"fmt"
 	"log"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,8 +14,14 @@ import (
 	"github.com/charmbracelet/bubbles/timer"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/palemoky/fight-the-landlord-go/internal/bot"
 	"github.com/palemoky/fight-the-landlord-go/internal/card"
 	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/palemoky/fight-the-landlord-go/internal/infer"
+	"github.com/palemoky/fight-the-landlord-go/internal/input"
+	"github.com/palemoky/fight-the-landlord-go/internal/match"
+	"github.com/palemoky/fight-the-landlord-go/internal/record"
+	"github.com/palemoky/fight-the-landlord-go/internal/rule"
 	"github.com/palemoky/fight-the-landlord-go/internal/utils"
 )
 
@@ -36,54 +46,104 @@ var (
 	boxStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder())
 	promptStyle  = lipgloss.NewStyle().MarginTop(1)
 	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	wildStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B4513")).Background(lipgloss.Color("#FFD700")).Bold(true)
+	bidInputs    = map[game.BidAction]string{game.BidPass: "PASS", game.BidOne: "1", game.BidTwo: "2", game.BidThree: "3", game.BidCall: "叫", game.BidRob: "抢"}
 	displayOrder = []card.Rank{card.RankRedJoker, card.RankBlackJoker, card.Rank2, card.RankA, card.RankK, card.RankQ, card.RankJ, card.Rank10, card.Rank9, card.Rank8, card.Rank7, card.Rank6, card.Rank5, card.Rank4, card.Rank3}
 )
 
 // model 是 Bubble Tea 应用的状态
 type model struct {
-	game   *game.Game
-	timer  timer.Model
-	input  textinput.Model
-	error  string
-	width  int
-	height int
+	game          *game.Game
+	match         *match.Match // 多局比赛，负责轮换先叫地主的玩家和记账
+	agents        []game.Agent // 每个座位的 Agent，人类玩家的座位由 human 驱动
+	human         *humanAgent
+	awaitingHuman bool              // 是否正在等待人类玩家输入
+	choices       []rule.ParsedHand // 出牌有多种牌型时，等待玩家选择的牌型
+	timer         timer.Model
+	input         textinput.Model
+	error         string
+	notice        string    // 结束界面的提示，例如棋谱保存的位置
+	history       *eventLog // 当前这一局的出牌记录
+	saved         string    // 存档并退出后在终端打印的提示
+	practice      bool      // 练习模式，可以悔棋
+	grouped       bool      // 是否在手牌下方显示理牌的结果
+	analysis      *analysis // 出牌阶段的形势估计和手牌推断，局面变化时重新计算
+	width         int
+	height        int
+}
+
+// Config 启动游戏的配置
+type Config struct {
+	Seed        int64  // 第一局的随机种子，为 0 时使用基于当前时间的种子
+	Hands       int    // 比赛的局数，0 表示一直玩到退出
+	TargetScore int    // 有玩家累计得分达到 ±TargetScore 时比赛结束，0 表示不限
+	Replay      string // 棋谱文件，非空时进入回放模式
+	Resume      string // 存档文件，非空时从存档继续对局
+	Practice    bool   // 练习模式，允许悔棋
+	Rules       string // 牌型规则的名字，为空时使用经典规则
 }
 
 // initialModel 初始化UI模型
-func initialModel() model {
-	g := game.NewGame()
-	g.Deal()
-	g.Bidding()
+func initialModel(cfg Config) model {
+	mcfg := match.Config{Hands: cfg.Hands, TargetScore: cfg.TargetScore, Seed: cfg.Seed, Rules: rule.Classic}
+	if cfg.Rules != "" {
+		rules, err := rule.ParseRuleSet(cfg.Rules)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		mcfg.Rules = rules
+	}
+	var mt *match.Match
+	var g *game.Game
+	var history *eventLog
+	if cfg.Resume != "" {
+		var err error
+		if mt, err = loadMatch(cfg.Resume, mcfg); err != nil {
+			log.Fatalf("读取存档时出错: %v", err)
+		}
+		g = mt.Game()
+		history = watchMatch(mt)
+		cfg.Practice = cfg.Practice || g.Practice
+	} else {
+		mt = match.New(mcfg)
+		history = watchMatch(mt) // 在第一局发牌之前订阅
+		var err error
+		if g, err = mt.NextGame(); err != nil {
+			log.Fatalf("开始比赛时出错: %v", err)
+		}
+	}
+	g.Practice = cfg.Practice
 
 	ti := textinput.New()
-	ti.Placeholder = "请出牌 (如 33344) 或 PASS 然后回车"
 	ti.Focus()
 	ti.CharLimit = 25
 	ti.Width = 50
 
-	tm := timer.NewWithInterval(game.PlayerTurnTimeout, time.Second)
-
+	human := newHumanAgent()
+	agents := []game.Agent{human}
+	for range len(g.Players) - 1 {
+		agents = append(agents, bot.NewPIMC(time.Second, 0))
+	}
 	return model{
-		game:  g,
-		timer: tm,
-		input: ti,
+		game:     g,
+		match:    mt,
+		history:  history,
+		practice: cfg.Practice,
+		agents:   agents,
+		human:    human,
+		timer:    timer.NewWithInterval(game.PlayerTurnTimeout, time.Second),
+		input:    ti,
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return m.timer.Start()
+	return func() tea.Msg { return turnStartMsg{} }
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
-	placeHolder := func() {
-		m.input.Placeholder = utils.Ternary(m.game.CanCurrentPlayerPlay,
-			"请出牌 (如 33344) 或 PASS",
-			"没有可出的牌, 请输入 PASS")
-	}
-
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -93,39 +153,80 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
 			return m, tea.Quit
+		case tea.KeyCtrlS:
+			// 存档并退出，之后可以用 --resume 继续
+			if _, isOver := m.game.CheckWinner(); !isOver {
+				path, err := m.saveGame()
+				if err != nil {
+					m.error = fmt.Sprintf("存档失败: %v", err)
+					return m, nil
+				}
+				m.saved = fmt.Sprintf("对局已保存到 %s，使用 --resume %s 继续", path, path)
+				return m, tea.Quit
+			}
+		case tea.KeyCtrlG:
+			m.grouped = !m.grouped
+			return m, nil
+		case tea.KeyCtrlZ:
+			if m.practice {
+				return m, m.undo()
+			}
+		case tea.KeyCtrlY:
+			if m.practice {
+				return m, m.redo()
+			}
+		case tea.KeyRunes:
+			// 游戏结束后按 S 保存棋谱，按 N 开始下一局
+			if _, isOver := m.game.CheckWinner(); isOver {
+				switch strings.ToLower(msg.String()) {
+				case "s":
+					m.notice = m.saveRecord()
+				case "n":
+					if g, err := m.match.NextGame(); err == nil {
+						g.Practice = m.practice
+						m.game, m.notice = g, ""
+						return m, func() tea.Msg { return turnStartMsg{} }
+					}
+				}
+				return m, nil
+			}
 		case tea.KeyEnter:
-			// 玩家提交出牌
-			if m.game.CurrentTurn == 0 { // 确保只有轮到玩家时才能提交
+			// 玩家提交叫地主或出牌
+			if m.awaitingHuman { // 确保只有轮到玩家时才能提交
 				input := m.input.Value()
 				m.input.Reset()
 				m.error = ""
 
-				err := m.game.PlayTurn(input)
-				if err != nil {
+				if done, err := m.submit(input); err != nil {
 					m.error = err.Error()
-				} else {
-					// 出牌成功，为下一位玩家重置计时器
-					placeHolder()
-
-					m.timer = timer.NewWithInterval(game.PlayerTurnTimeout, time.Second)
-					cmds = append(cmds, m.timer.Start())
+				} else if done {
+					m.awaitingHuman = false
 				}
 			}
 			return m, tea.Batch(cmds...)
 		}
 
+	case turnStartMsg:
+		cmds = append(cmds, m.nextTurn())
+
+	case agentBidMsg:
+		cmds = append(cmds, m.applyBid(msg))
+
+	case agentPlayMsg:
+		cmds = append(cmds, m.applyPlay(msg))
+
 	case timer.TimeoutMsg:
-		m.error = ""
-		// 超时，自动出牌
-		err := m.game.PlayTurn("") // 游戏逻辑会处理空字符串作为超时
-		if err != nil {
-			m.error = err.Error()
+		// 超时，叫地主阶段自动不叫，出牌阶段自动出牌
+		if msg.ID == m.timer.ID() && m.awaitingHuman {
+			m.error = ""
+			m.awaitingHuman = false
+			m.choices = nil
+			if m.game.Phase == game.PhaseBidding {
+				m.human.bids <- game.BidPass
+			} else {
+				m.human.plays <- m.timeoutHand()
+			}
 		}
-		placeHolder()
-		// 超时出牌更新记牌器
-		// 为下一位玩家重置计时器
-		m.timer = timer.NewWithInterval(game.PlayerTurnTimeout, time.Second)
-		cmds = append(cmds, m.timer.Start())
 	}
 
 	m.timer, cmd = m.timer.Update(msg)
@@ -137,6 +238,81 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// submit 校验玩家的叫地主或出牌输入，合法时交给人类玩家的 Agent，done 表示已经交出
+// 出牌有多种牌型时先列出选项，玩家输入序号后才交出。
+func (m *model) submit(text string) (done bool, err error) {
+	if m.game.Phase == game.PhaseBidding {
+		action, err := input.ParseBid(text)
+		if err != nil {
+			return false, err
+		}
+		if !slices.Contains(m.game.Auction.ValidActions(), action) {
+			return false, fmt.Errorf("现在不能%s", action)
+		}
+		m.human.bids <- action
+		return true, nil
+	}
+
+	if len(m.choices) > 0 {
+		// 输入序号选择牌型，输入其他内容视为重新出牌
+		if n, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+			if n < 1 || n > len(m.choices) {
+				return false, fmt.Errorf("请输入 1-%d 选择牌型", len(m.choices))
+			}
+			m.human.plays <- m.choices[n-1]
+			m.choices = nil
+			return true, nil
+		}
+		m.choices = nil
+	}
+
+	cards, err := input.ParsePlay(text, m.game.Players[humanSeat].Hand)
+	if err != nil {
+		return false, err
+	}
+	if cards == nil {
+		if err := m.game.ValidatePass(); err != nil {
+			return false, err
+		}
+		m.human.plays <- rule.ParsedHand{}
+		return true, nil
+	}
+	options, err := m.game.Interpretations(cards)
+	if err != nil {
+		return false, err
+	}
+	if len(options) > 1 {
+		m.choices = options
+		m.input.Placeholder = "输入序号选择牌型，或者重新出牌"
+		return false, nil
+	}
+	m.human.plays <- options[0]
+	return true, nil
+}
+
+// updatePlaceholder 根据当前阶段更新输入框提示
+func (m *model) updatePlaceholder() {
+	if m.game.Phase == game.PhaseBidding {
+		m.input.Placeholder = m.bidPlaceholder()
+		return
+	}
+	m.input.Placeholder = utils.Ternary(m.game.CanCurrentPlayerPlay,
+		"请出牌 (如 33344) 或 PASS",
+		"没有可出的牌, 请输入 PASS")
+}
+
+// bidPlaceholder 根据当前可叫的动作生成叫地主提示
+func (m model) bidPlaceholder() string {
+	if m.game.Auction == nil {
+		return ""
+	}
+	var options []string
+	for _, a := range m.game.Auction.ValidActions() {
+		options = append(options, bidInputs[a])
+	}
+	return "请叫地主: " + strings.Join(options, " / ")
+}
+
 func (m model) View() string {
 	if m.width == 0 {
 		return "Loading..."
@@ -149,18 +325,24 @@ func (m model) View() string {
 
 	// 顶部: 标题, 记牌器, 底牌
 	title := titleStyle("FIGHT THE LANDLORD")
-	note := "输入 Note: T->10; BJ->Black Joker; RJ->Red Joker; Pass"
-	counter := m.renderCardCounter()
+	note := "输入 Note: T->10; BJ->Black Joker; RJ->Red Joker; JOKER->王炸; Pass; Ctrl+G 理牌; Ctrl+S 存档并退出"
+	if m.practice {
+		note += "; Ctrl+Z 悔棋; Ctrl+Y 重做"
+	}
+	counter := m.renderCardCounter(humanSeat)
 	landlordCards := m.renderLandlordCards()
 	greetContent := lipgloss.JoinVertical(lipgloss.Center, title, note)
-	counterContent := lipgloss.JoinHorizontal(lipgloss.Center, counter, landlordCards)
+	counterContent := lipgloss.JoinHorizontal(lipgloss.Center, counter, m.renderOdds(), landlordCards)
 	topContent := lipgloss.JoinVertical(lipgloss.Center, greetContent, counterContent)
 	topSection := lipgloss.PlaceHorizontal(m.width, lipgloss.Center, topContent)
 
-	// 中部: 其他玩家信息及上家出牌信息
+	// 中部: 其他玩家信息及上家出牌信息，下家在左，上家在右，四人时对家在上方，两人时只有左边的对手
 	player2View := m.renderOtherPlayer(1)
-	lastPlayView := m.renderLastPlay()
-	player3View := m.renderOtherPlayer(2)
+	lastPlayView := lipgloss.JoinVertical(lipgloss.Center, m.renderLastPlay(), m.renderHistory())
+	player3View := ""
+	if len(m.game.Players) > 2 {
+		player3View = m.renderOtherPlayer(len(m.game.Players) - 1)
+	}
 	// 总宽度 - 三个组件的宽度 = 剩余空间
 	usedWidth := lipgloss.Width(player2View) + lipgloss.Width(lastPlayView) + lipgloss.Width(player3View)
 	remainingSpace := m.width - usedWidth - (docStyle.GetHorizontalMargins() * 2)
@@ -169,6 +351,10 @@ func (m model) View() string {
 	spacerWidth := max(remainingSpace/2, 0)
 	spacer := lipgloss.NewStyle().Width(spacerWidth).Render()
 	middleSection := lipgloss.JoinHorizontal(lipgloss.Top, player2View, spacer, lastPlayView, spacer, player3View)
+	if len(m.game.Players) == 4 {
+		acrossView := lipgloss.PlaceHorizontal(lipgloss.Width(middleSection), lipgloss.Center, m.renderOtherPlayer(2))
+		middleSection = lipgloss.JoinVertical(lipgloss.Left, acrossView, middleSection)
+	}
 
 	// 底部: 你的手牌和输入提示
 	myHand := m.renderPlayerHand(m.game.Players[0].Hand)
@@ -185,21 +371,21 @@ func (m model) renderCard(c card.Card, content string) string {
 	return utils.Ternary(c.Color == card.Red, redStyle.Render(content), blackStyle.Render(content))
 }
 
-func (m model) renderCardCounter() string {
-	// 获取总牌数
+// renderCardCounter 显示座位 seat 看不到的牌，seat 为 -1 时显示所有还没打出的牌
+func (m model) renderCardCounter(seat int) string {
 	remaining := m.game.CardCounter.GetRemainingCards()
-
-	// 统计用户手中的牌
-	handCounter := make(map[card.Rank]int)
-	for _, card := range m.game.Players[0].Hand {
-		handCounter[card.Rank]++
+	if seat >= 0 {
+		remaining = m.game.View(seat).Unseen // 记牌器减去自己的手牌
 	}
 
-	// 根据用户手牌显示剩余牌数
+	// 显示每种点数剩余的张数
 	var rankStr, countStr strings.Builder
 	for _, r := range displayOrder {
+		if r < m.game.Rules.LowestRank {
+			continue // 这一局没有用到的点数
+		}
 		rankStr.WriteString(fmt.Sprintf(" %-2s", r.String()))
-		leftCount := remaining[r] - handCounter[r]
+		leftCount := remaining[r]
 
 		countStr.WriteString(utils.Ternary(leftCount > 0,
 			grayStyle.MarginLeft(1).Render(fmt.Sprintf("%-2d", leftCount)),
@@ -216,17 +402,39 @@ func (m model) renderLandlordCards() string {
 
 	var rankSB, suitSB strings.Builder
 	for _, c := range m.game.LandlordCards {
-		var style lipgloss.Style
-		style = utils.Ternary(c.Color == card.Red, redStyle, blackStyle)
-		style = style.Align(lipgloss.Center).Margin(0, 1)
+		// 地主确定之前底牌背面朝上
+		if !m.game.LandlordCardsRevealed() {
+			style := grayStyle.Align(lipgloss.Center).Margin(0, 1)
+			rankSB.WriteString(style.Render("??"))
+			suitSB.WriteString(style.Render("??"))
+			continue
+		}
+		style := m.cardStyle(c).Align(lipgloss.Center).Margin(0, 1)
 		rankSB.WriteString(style.Render(fmt.Sprintf("%-2s", c.Rank.String())))
 		suitSB.WriteString(style.Render(fmt.Sprintf("%-2s", c.Suit.String())))
 	}
 
-	content := lipgloss.JoinVertical(lipgloss.Center, "底牌", rankSB.String(), suitSB.String())
+	title := utils.Ternary(m.game.BaseScore > 0, fmt.Sprintf("底牌 (底分 %d 倍数 ×%d)", m.game.BaseScore, m.game.Multiplier()), "底牌")
+	if m.game.Rules.Wild != 0 {
+		title += " " + wildStyle.Render(fmt.Sprintf("癞子 %s", m.game.Rules.Wild))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Center, title, rankSB.String(), suitSB.String())
 	return boxStyle.Render(content)
 }
 
+// renderBids 叫地主阶段显示每个玩家最近一次的叫地主动作
+func (m model) renderBids(idx int) string {
+	if m.game.Phase != game.PhaseBidding || m.game.Auction == nil {
+		return ""
+	}
+	for i := len(m.game.Auction.History) - 1; i >= 0; i-- {
+		if bid := m.game.Auction.History[i]; bid.Seat == idx {
+			return fmt.Sprintf(" 📣 %s", bid.Action)
+		}
+	}
+	return ""
+}
+
 func (m model) renderOtherPlayer(idx int) string {
 	p := m.game.Players[idx]
 	icon := utils.Ternary(p.IsLandlord, LandlordIcon, FarmerIcon)
@@ -236,15 +444,94 @@ func (m model) renderOtherPlayer(idx int) string {
 		nameStyle = nameStyle.Foreground(lipgloss.Color("220")).Bold(true)
 	}
 	name := nameStyle.Render(fmt.Sprintf(" %s %s", icon, p.Name))
-	cardsLeft := fmt.Sprintf(" 🃏 剩余: %d", len(p.Hand))
+	cardsLeft := fmt.Sprintf(" 🃏 剩余: %d", m.game.View(humanSeat).HandSizes[idx]) // 对手的手牌只能通过视角看到张数
 	nameLine := utils.Ternary(m.game.CurrentTurn == idx,
-		lipgloss.JoinHorizontal(lipgloss.Left, name, " ",
-			fmt.Sprintf("(⏳ %s)", m.timer.View())), name)
+		lipgloss.JoinHorizontal(lipgloss.Left, name, " ", "(🤔 思考中)"), name)
 
 	content := lipgloss.JoinVertical(lipgloss.Left, nameLine, cardsLeft)
+	if bid := m.renderBids(idx); bid != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, bid)
+	}
+	if guess := m.renderInference(idx); guess != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, guess)
+	}
 	return boxStyle.Width(22).Render(content)
 }
 
+// analysis 你的视角下的形势估计和对手手牌推断
+// 抽样和推断比较耗时，局面变化时由 analyze 计算一次，View 只显示结果。
+type analysis struct {
+	unseen    map[card.Rank]int // 你看不到的牌
+	inference *infer.Inference
+	bomb      float64 // 对手有炸弹的概率
+	split     float64 // 王炸被拆开的概率
+}
+
+// oddsSamples 形势估计每次抽样的次数
+const oddsSamples = 200
+
+// analyze 按当前局面重新计算形势估计和手牌推断，不在出牌阶段时清空
+// 随机数按已经出过的手数取种子，同一个局面每次计算的数字不变。
+func (m *model) analyze() {
+	m.analysis = nil
+	if m.game.Phase != game.PhasePlaying {
+		return
+	}
+	view := m.game.View(humanSeat)
+	sampler := infer.NewSampler(view)
+	sampler.Weight = infer.BidWeight(view)
+	rng := rand.New(rand.NewSource(int64(len(m.game.Moves))))
+	m.analysis = &analysis{
+		unseen:    view.Unseen,
+		inference: infer.Infer(view),
+		bomb: sampler.Probability(rng, oddsSamples, infer.AnyOpponent(view, func(seat int) infer.Event {
+			return infer.HoldsBomb(view.Rules, seat)
+		})),
+		split: sampler.Probability(rng, oddsSamples, infer.RocketSplit(view.Rules)),
+	}
+}
+
+// renderInference 显示从你的视角推断出的对手手牌：一定有的点数和已经可以排除的点数
+func (m model) renderInference(idx int) string {
+	if m.analysis == nil {
+		return ""
+	}
+	h := m.analysis.inference.Seats[idx]
+	var lines []string
+	if certain := h.Certain(); len(certain) > 0 {
+		lines = append(lines, " ✅ 一定有: "+record.FormatRanks(certain))
+	}
+	var lacks []card.Rank
+	for r := card.Rank3; r <= card.RankRedJoker; r++ {
+		if m.analysis.unseen[r] > 0 && h.Max[r] == 0 {
+			lacks = append(lacks, r)
+		}
+	}
+	if len(lacks) > 0 {
+		lines = append(lines, " 🚫 没有: "+record.FormatRanks(lacks))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderOdds 出牌阶段显示按你看到的信息抽样估计的对手有炸弹和王炸被拆开的概率
+func (m model) renderOdds() string {
+	if m.analysis == nil {
+		return ""
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, "形势估计",
+		fmt.Sprintf("对手有炸弹 %3.0f%%", m.analysis.bomb*100),
+		fmt.Sprintf("王炸被拆开 %3.0f%%", m.analysis.split*100))
+	return boxStyle.Render(content)
+}
+
+// cardStyle 牌面的样式，癞子用金色标出
+func (m model) cardStyle(c card.Card) lipgloss.Style {
+	if m.game.Rules.IsWild(c) {
+		return wildStyle
+	}
+	return utils.Ternary(c.Color == card.Red, redStyle, blackStyle)
+}
+
 func (m model) renderFancyHand(hand []card.Card) string {
 	if len(hand) == 0 {
 		return "(无)"
@@ -255,10 +542,10 @@ func (m model) renderFancyHand(hand []card.Card) string {
 
 	// 遍历除了最后一张牌之外的所有牌
 	for _, c := range hand[:len(hand)-1] {
-		style := utils.Ternary(c.Color == card.Red, redStyle, blackStyle)
+		style := m.cardStyle(c)
 
-		// 格式化点数和花色，确保'10'和'9'对齐
-		rankStr := fmt.Sprintf("%-2s", c.Rank.String())
+		// 格式化点数和花色，确保'10'和'9'对齐；打出的癞子显示它代替的点数
+		rankStr := fmt.Sprintf("%-2s", c.EffectiveRank().String())
 		suitStr := fmt.Sprintf("%-2s", c.Suit.String())
 
 		// 为每一张重叠的牌只渲染左侧部分
@@ -270,8 +557,8 @@ func (m model) renderFancyHand(hand []card.Card) string {
 
 	// 单独处理最后一张牌，渲染一个完整的、封闭的盒子
 	lastCard := hand[len(hand)-1]
-	style := utils.Ternary(lastCard.Color == card.Red, redStyle, blackStyle)
-	rankStr := fmt.Sprintf("%-2s", lastCard.Rank.String())
+	style := m.cardStyle(lastCard)
+	rankStr := fmt.Sprintf("%-2s", lastCard.EffectiveRank().String())
 	suitStr := fmt.Sprintf("%-2s", lastCard.Suit.String())
 
 	top.WriteString(TopBorderEnd)
@@ -290,17 +577,50 @@ func (m model) renderFancyHand(hand []card.Card) string {
 
 func (m model) renderPlayerHand(hand []card.Card) string {
 	handView := m.renderFancyHand(hand)
+	if m.grouped && len(hand) > 0 {
+		handView = lipgloss.JoinVertical(lipgloss.Left, handView, m.renderGroups(hand))
+	}
 	return lipgloss.NewStyle().MarginTop(1).Render(lipgloss.JoinVertical(lipgloss.Left, handView))
 }
 
+// renderGroups 显示按最少手数理好的手牌
+func (m model) renderGroups(hand []card.Card) string {
+	plays := rule.Decompose(m.game.Rules, hand)
+	groups := make([]string, len(plays))
+	for i, p := range plays {
+		groups[i] = formatHand(p)
+	}
+	return grayStyle.Render(fmt.Sprintf("🧩 理牌 (%d 手): %s", len(plays), strings.Join(groups, " | ")))
+}
+
 func (m model) renderTurnPrompt() string {
 	currentPlayer := m.game.Players[m.game.CurrentTurn]
 	var sb strings.Builder
 
 	// 根据轮到谁来显示不同的提示和计时器
 	prompt := fmt.Sprintf("⏳ %s", m.timer.View())
+	if m.game.Phase == game.PhaseBidding {
+		if m.game.CurrentTurn == 0 {
+			sb.WriteString(fmt.Sprintf("轮到你叫地主了, %s! %s\n", currentPlayer.Name, prompt))
+			sb.WriteString(m.renderBidAdvice() + "\n")
+			sb.WriteString(m.input.View())
+			if m.error != "" {
+				sb.WriteString("\n" + errorStyle.Render(m.error))
+			}
+		} else {
+			sb.WriteString(fmt.Sprintf("等待 %s 叫地主...", currentPlayer.Name))
+		}
+		return promptStyle.Render(sb.String())
+	}
+
 	if m.game.CurrentTurn == 0 {
 		sb.WriteString(fmt.Sprintf("轮到你了, %s! %s\n", currentPlayer.Name, prompt))
+		if len(m.choices) > 0 {
+			sb.WriteString("这手牌有多种牌型:\n")
+			for i, h := range m.choices {
+				sb.WriteString(fmt.Sprintf("  %d. %s %s\n", i+1, h.Type, formatHand(h)))
+			}
+		}
 		sb.WriteString(m.input.View())
 		if m.error != "" {
 			sb.WriteString("\n" + errorStyle.Render(m.error))
@@ -311,6 +631,13 @@ func (m model) renderTurnPrompt() string {
 	return promptStyle.Render(sb.String())
 }
 
+// renderBidAdvice 按手牌的实力估计给出建议的叫地主动作
+func (m model) renderBidAdvice() string {
+	view := m.game.View(humanSeat)
+	s := rule.Evaluate(view.Rules, view.Hand)
+	return grayStyle.Render(fmt.Sprintf("💡 建议: %s (牌力评分 %.0f/100, 估计 %d 手出完)", bot.SuggestBid(view), s.Score*100, s.Plays))
+}
+
 func (m model) renderLastPlay() string {
 	const placeholderHeight = 6 // 定义占位符的固定高度
 
@@ -336,17 +663,128 @@ func (m model) renderLastPlay() string {
 
 func (m model) gameOverView(winner *game.Player) string {
 	winnerType := utils.Ternary(winner.IsLandlord, "地主", "农民")
-	msg := fmt.Sprintf("GAME OVER\n\n🥳 %s (%s) 获胜! 🎉\n\n按 Ctrl+C 或 Esc 退出", winnerType, winner.Name)
+	next := utils.Ternary(m.match.Over(), "比赛结束! 按 S 保存棋谱，按 Ctrl+C 或 Esc 退出", "按 N 开始下一局，按 S 保存棋谱，按 Ctrl+C 或 Esc 退出")
+	msg := fmt.Sprintf("GAME OVER\n\n🥳 %s (%s) 获胜! 🎉\n\n%s\n\n本局种子: %d（使用 --seed %d 重玩这一局）",
+		winnerType, winner.Name, m.renderSettlement(), m.game.Seed, m.game.Seed)
+	content := lipgloss.JoinVertical(lipgloss.Center, msg, m.renderStandings(), next)
+	if m.notice != "" {
+		content = lipgloss.JoinVertical(lipgloss.Center, content, "", m.notice)
+	}
 	return lipgloss.NewStyle().
 		Width(m.width).
 		Align(lipgloss.Center).
-		Render(msg)
+		Render(content)
+}
+
+// renderStandings 显示比赛的累计成绩
+func (m model) renderStandings() string {
+	progress := fmt.Sprintf("第 %d 局", len(m.match.Results))
+	if m.match.Hands > 0 {
+		progress += fmt.Sprintf(" / 共 %d 局", m.match.Hands)
+	}
+	if m.match.TargetScore > 0 {
+		progress += fmt.Sprintf("，目标 ±%d 分", m.match.TargetScore)
+	}
+
+	// 中文和 emoji 的宽度与字节数不同，用 lipgloss 按显示宽度对齐
+	row := func(cells ...string) string {
+		widths := []int{6, 18, 8, 6}
+		for i := range cells {
+			cells[i] = lipgloss.NewStyle().Width(widths[i]).Render(cells[i])
+		}
+		return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+	}
+	lines := []string{progress, row("排名", "玩家", "累计", "胜局")}
+	for i, s := range m.match.Standings() {
+		lines = append(lines, row(strconv.Itoa(i+1), s.Name, fmt.Sprintf("%+d", s.Points), strconv.Itoa(s.Wins)))
+	}
+	return boxStyle.Margin(1, 0).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// renderSettlement 显示结算明细：底分、各项翻倍和每个玩家的得分
+func (m model) renderSettlement() string {
+	s, err := m.game.Settle()
+	if err != nil {
+		return ""
+	}
+
+	var details []string
+	if s.Bombs > 0 {
+		details = append(details, fmt.Sprintf("炸弹 ×%d", 1<<s.Bombs))
+	}
+	if s.Rockets > 0 {
+		details = append(details, fmt.Sprintf("王炸 ×%d", 1<<s.Rockets))
+	}
+	if s.Spring {
+		details = append(details, "春天 ×2")
+	}
+	if s.AntiSpring {
+		details = append(details, "反春 ×2")
+	}
+	summary := fmt.Sprintf("底分 %d × 倍数 %d", s.BaseScore, s.Multiplier)
+	if len(details) > 0 {
+		summary += fmt.Sprintf("（%s）", strings.Join(details, " "))
+	}
+	if len(m.game.Players) == 2 {
+		summary += "，农民一人输赢两份"
+	}
+	lines := []string{summary}
+	for i, p := range m.game.Players {
+		lines = append(lines, fmt.Sprintf("%s %s: %+d", utils.Ternary(p.IsLandlord, LandlordIcon, FarmerIcon), p.Name, s.Points[i]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// saveRecord 把这一局的棋谱保存到当前目录，返回给玩家看的提示
+func (m model) saveRecord() string {
+	path := fmt.Sprintf("ddz-%d.txt", m.game.Seed)
+	if err := os.WriteFile(path, []byte(m.game.Record().String()), 0o644); err != nil {
+		return errorStyle.Render(fmt.Sprintf("保存棋谱失败: %v", err))
+	}
+	return fmt.Sprintf("棋谱已保存到 %s，使用 --replay %s 回放", path, path)
+}
+
+// saveGame 把比赛的进度和当前这一局保存到 ddz-<种子>.json
+func (m model) saveGame() (string, error) {
+	path := fmt.Sprintf("ddz-%d.json", m.game.Seed)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := m.match.Save(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// loadMatch 读取存档文件，只有一局的旧存档按 cfg 开始新的比赛
+func loadMatch(path string, cfg match.Config) (*match.Match, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return match.Load(f, cfg)
 }
 
 // Start 启动UI
-func Start() {
-	_, err := tea.NewProgram(initialModel(), tea.WithAltScreen()).Run()
+func Start(cfg Config) {
+	var m tea.Model
+	if cfg.Replay != "" {
+		rm, err := loadReplay(cfg.Replay)
+		if err != nil {
+			log.Fatalf("加载棋谱时出错: %v", err)
+		}
+		m = rm
+	} else {
+		m = initialModel(cfg)
+	}
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
 	if err != nil {
 		log.Fatalf("启动UI时出错: %v", err)
 	}
+	if fm, ok := final.(model); ok && fm.saved != "" {
+		fmt.Println(fm.saved)
+	}
 }
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/palemoky/fight-the-landlord-go/internal/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModel_Analyze checks that odds and inference are computed for the playing phase only.
+func TestModel_Analyze(t *testing.T) {
+	g := game.NewGameWithSeed(4)
+	g.Deal()
+	g.StartBidding(humanSeat)
+	m := model{game: g}
+
+	m.analyze()
+	assert.Nil(t, m.analysis, "nothing to estimate while bidding")
+	assert.Empty(t, m.renderOdds())
+
+	require.NoError(t, g.Bid(game.BidThree))
+	m.analyze()
+	require.NotNil(t, m.analysis)
+	assert.Len(t, m.analysis.inference.Seats, len(g.Players))
+	assert.InDelta(t, 0.5, m.analysis.bomb, 0.5)
+	assert.NotEmpty(t, m.renderOdds())
+
+	// 同一个局面重新计算的结果不变
+	first := *m.analysis
+	m.analyze()
+	assert.Equal(t, first.bomb, m.analysis.bomb)
+	assert.Equal(t, first.split, m.analysis.split)
+}